@@ -0,0 +1,341 @@
+package vector
+
+// bTreeNode is a node of the counted B-tree. Leaves keep elements, internal
+// nodes keep children. Every node knows the count of elements in its subtree.
+type bTreeNode[T any] struct {
+	items    []T
+	children []*bTreeNode[T]
+	size     int
+}
+
+// leaf checks if the node is a leaf
+func (n *bTreeNode[T]) leaf() bool {
+	return n.children == nil
+}
+
+// entries returns the count of node entries (elements of leaf or children of internal node)
+func (n *bTreeNode[T]) entries() int {
+	if n.leaf() {
+		return len(n.items)
+	}
+	return len(n.children)
+}
+
+// locate returns the child index what holds the element at index and the index inside the child.
+// If atEnd is set, the index equal to the child size is located in that child (used by insert).
+func (n *bTreeNode[T]) locate(index int, atEnd bool) (child int, childIndex int) {
+	for child = 0; child < len(n.children)-1; child++ {
+		size := n.children[child].size
+		if index < size || (atEnd && index == size) {
+			break
+		}
+		index -= size
+	}
+	return child, index
+}
+
+// split moves the upper half of the node entries into the new node and returns it
+func (n *bTreeNode[T]) split() *bTreeNode[T] {
+	ret := &bTreeNode[T]{}
+	if n.leaf() {
+		half := len(n.items) / 2
+		ret.items = append(make([]T, 0, len(n.items)), n.items[half:]...)
+		n.items = clearTail(n.items, half)
+		ret.size = len(ret.items)
+		n.size = len(n.items)
+		return ret
+	}
+
+	half := len(n.children) / 2
+	ret.children = append(make([]*bTreeNode[T], 0, len(n.children)), n.children[half:]...)
+	n.children = clearTail(n.children, half)
+	for _, child := range ret.children {
+		ret.size += child.size
+	}
+	n.size -= ret.size
+	return ret
+}
+
+// clearTail zeroes the slice elements after count and returns the slice of count elements
+func clearTail[E any](data []E, count int) []E {
+	var zero E
+	for i := count; i < len(data); i++ {
+		data[i] = zero
+	}
+	return data[:count]
+}
+
+// insertAt inserts value into the slice at index
+func insertAt[E any](data []E, index int, value E) []E {
+	var zero E
+	data = append(data, zero)
+	copy(data[index+1:], data[index:])
+	data[index] = value
+	return data
+}
+
+// removeAt removes the element at index from the slice
+func removeAt[E any](data []E, index int) []E {
+	copy(data[index:], data[index+1:])
+	return clearTail(data, len(data)-1)
+}
+
+// bTree is a counted B-tree what keeps a sequence of elements and supports
+// positional access, insertion and removal in O(log n).
+type bTree[T any] struct {
+	root   *bTreeNode[T]
+	degree int
+}
+
+// newBTree creates an empty counted B-tree. Every node except the root keeps from
+// degree to 2*degree entries.
+func newBTree[T any](degree int) *bTree[T] {
+	if degree < 2 {
+		degree = 2
+	}
+	return &bTree[T]{
+		root:   &bTreeNode[T]{},
+		degree: degree,
+	}
+}
+
+// len returns the count of elements
+func (t *bTree[T]) len() int {
+	return t.root.size
+}
+
+// get returns the element at index
+func (t *bTree[T]) get(index uint) T {
+	if int(index) >= t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	i := int(index)
+	node := t.root
+	for !node.leaf() {
+		var child int
+		child, i = node.locate(i, false)
+		node = node.children[child]
+	}
+	return node.items[i]
+}
+
+// set sets the element at index
+func (t *bTree[T]) set(index uint, value T) {
+	if int(index) >= t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	i := int(index)
+	node := t.root
+	for !node.leaf() {
+		var child int
+		child, i = node.locate(i, false)
+		node = node.children[child]
+	}
+	node.items[i] = value
+}
+
+// insertInto inserts value into the subtree at index and returns the split off node on overflow
+func (t *bTree[T]) insertInto(node *bTreeNode[T], index int, value T) *bTreeNode[T] {
+	node.size++
+
+	if node.leaf() {
+		node.items = insertAt(node.items, index, value)
+	} else {
+		child, childIndex := node.locate(index, true)
+		if split := t.insertInto(node.children[child], childIndex, value); split != nil {
+			node.children = insertAt(node.children, child+1, split)
+		}
+	}
+
+	if node.entries() > 2*t.degree {
+		return node.split()
+	}
+	return nil
+}
+
+// insert inserts elements at index
+func (t *bTree[T]) insert(index uint, args ...T) {
+	if int(index) > t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	for offset, value := range args {
+		if split := t.insertInto(t.root, int(index)+offset, value); split != nil {
+			left := t.root
+			t.root = &bTreeNode[T]{
+				children: []*bTreeNode[T]{left, split},
+				size:     left.size + split.size,
+			}
+		}
+	}
+}
+
+// append appends elements to the end
+func (t *bTree[T]) append(args ...T) {
+	t.insert(uint(t.root.size), args...)
+}
+
+// removeFrom removes the element at index from the subtree
+func (t *bTree[T]) removeFrom(node *bTreeNode[T], index int) (ret T) {
+	node.size--
+
+	if node.leaf() {
+		ret = node.items[index]
+		node.items = removeAt(node.items, index)
+		return
+	}
+
+	child, childIndex := node.locate(index, false)
+	ret = t.removeFrom(node.children[child], childIndex)
+	if node.children[child].entries() < t.degree {
+		t.rebalance(node, child)
+	}
+	return
+}
+
+// rebalance fixes the underflow of the child by borrowing an entry from a sibling or
+// merging the child with a sibling
+func (t *bTree[T]) rebalance(node *bTreeNode[T], child int) {
+	target := node.children[child]
+
+	if child > 0 && node.children[child-1].entries() > t.degree {
+		left := node.children[child-1]
+		if target.leaf() {
+			last := len(left.items) - 1
+			target.items = insertAt(target.items, 0, left.items[last])
+			left.items = clearTail(left.items, last)
+			left.size--
+			target.size++
+		} else {
+			last := len(left.children) - 1
+			moved := left.children[last]
+			target.children = insertAt(target.children, 0, moved)
+			left.children = clearTail(left.children, last)
+			left.size -= moved.size
+			target.size += moved.size
+		}
+		return
+	}
+
+	if child < len(node.children)-1 && node.children[child+1].entries() > t.degree {
+		right := node.children[child+1]
+		if target.leaf() {
+			target.items = append(target.items, right.items[0])
+			right.items = removeAt(right.items, 0)
+			right.size--
+			target.size++
+		} else {
+			moved := right.children[0]
+			target.children = append(target.children, moved)
+			right.children = removeAt(right.children, 0)
+			right.size -= moved.size
+			target.size += moved.size
+		}
+		return
+	}
+
+	// Merge with a sibling, the left one is preferred.
+	left := child - 1
+	if left < 0 {
+		left = child
+	}
+	if left+1 >= len(node.children) {
+		return
+	}
+
+	lhs := node.children[left]
+	rhs := node.children[left+1]
+	lhs.items = append(lhs.items, rhs.items...)
+	lhs.children = append(lhs.children, rhs.children...)
+	lhs.size += rhs.size
+	node.children = removeAt(node.children, left+1)
+}
+
+// remove removes the element at index
+func (t *bTree[T]) remove(index uint) T {
+	if t.root.size == 0 {
+		panic(ErrEmptyVector)
+	}
+	if int(index) >= t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	ret := t.removeFrom(t.root, int(index))
+	if !t.root.leaf() && len(t.root.children) == 1 {
+		t.root = t.root.children[0]
+	}
+	return ret
+}
+
+// removeRange removes the elements in [lo, hi) range
+func (t *bTree[T]) removeRange(lo, hi int) {
+	if lo < 0 || lo > hi || hi > t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	for count := lo; count < hi; count++ {
+		t.remove(uint(lo))
+	}
+}
+
+// truncate removes all elements starting from count
+func (t *bTree[T]) truncate(count int) {
+	if count < 0 || count > t.root.size {
+		panic(ErrIndexOutOfRange)
+	}
+
+	if count == 0 {
+		t.root = &bTreeNode[T]{}
+		return
+	}
+	for t.root.size > count {
+		t.remove(uint(t.root.size - 1))
+	}
+}
+
+// reset replaces all elements by data
+func (t *bTree[T]) reset(data []T) {
+	t.root = &bTreeNode[T]{}
+	t.append(data...)
+}
+
+// newEmpty creates a new empty B-tree of the same degree
+func (t *bTree[T]) newEmpty() orderedStorage[T] {
+	return newBTree[T](t.degree)
+}
+
+// xrange calls the callback for each element
+func (t *bTree[T]) xrange(callback func(index int, value T) error) error {
+	index := 0
+	var walk func(node *bTreeNode[T]) error
+	walk = func(node *bTreeNode[T]) error {
+		if node.leaf() {
+			for _, value := range node.items {
+				if err := callback(index, value); err != nil {
+					return err
+				}
+				index++
+			}
+			return nil
+		}
+		for _, child := range node.children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(t.root)
+}
+
+// Data returns a copy of the elements
+func (t *bTree[T]) Data() []T {
+	ret := make([]T, 0, t.root.size)
+	t.xrange(func(_ int, value T) error {
+		ret = append(ret, value)
+		return nil
+	})
+	return ret
+}
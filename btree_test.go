@@ -0,0 +1,88 @@
+package vector
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBTree_Model(t *testing.T) {
+	for _, degree := range []int{2, 3, 16} {
+		tree := newBTree[int](degree)
+		model := NewVector[int]()
+		random := rand.New(rand.NewSource(int64(degree)))
+
+		for step := 0; step < 5000; step++ {
+			if model.len() > 0 && random.Intn(3) == 0 {
+				index := uint(random.Intn(model.len()))
+				assert.Equal(t, model.remove(index), tree.remove(index))
+			} else {
+				index := uint(random.Intn(model.len() + 1))
+				model.insert(index, step)
+				tree.insert(index, step)
+			}
+			assert.Equal(t, model.len(), tree.len())
+		}
+
+		assert.Equal(t, model.Data(), tree.Data())
+		for index := 0; index < model.len(); index++ {
+			assert.Equal(t, model.get(uint(index)), tree.get(uint(index)))
+		}
+
+		for model.len() > 0 {
+			index := uint(random.Intn(model.len()))
+			assert.Equal(t, model.remove(index), tree.remove(index))
+		}
+		assert.Equal(t, 0, tree.len())
+		assert.True(t, tree.root.leaf())
+	}
+}
+
+func TestBTree_Bounds(t *testing.T) {
+	tree := newBTree[int](2)
+	assert.Panics(t, func() {
+		tree.remove(0)
+	})
+	assert.Panics(t, func() {
+		tree.insert(1, 1)
+	})
+
+	tree.append(1, 2, 3)
+	tree.set(1, 20)
+	assert.Equal(t, []int{1, 20, 3}, tree.Data())
+	assert.Panics(t, func() {
+		tree.get(3)
+	})
+	assert.Panics(t, func() {
+		tree.set(3, 1)
+	})
+}
+
+func TestStorage_RemoveRange(t *testing.T) {
+	storages := map[string]func() orderedStorage[int]{
+		"vector":   func() orderedStorage[int] { return NewVector[int]() },
+		"btree":    func() orderedStorage[int] { return newBTree[int](2) },
+		"reversed": func() orderedStorage[int] { return reverseStorage[int](NewVector[int]()) },
+	}
+	for name, create := range storages {
+		t.Run(name, func(t *testing.T) {
+			storage := create()
+			storage.append(0, 1, 2, 3, 4, 5, 6, 7)
+
+			storage.removeRange(2, 5)
+			assert.Equal(t, []int{0, 1, 5, 6, 7}, storage.Data())
+			storage.removeRange(3, 3)
+			assert.Equal(t, []int{0, 1, 5, 6, 7}, storage.Data())
+			storage.removeRange(0, 2)
+			assert.Equal(t, []int{5, 6, 7}, storage.Data())
+
+			assert.PanicsWithValue(t, ErrIndexOutOfRange, func() {
+				storage.removeRange(2, 4)
+			})
+			assert.PanicsWithValue(t, ErrIndexOutOfRange, func() {
+				storage.removeRange(2, 1)
+			})
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package vector
+
+import "context"
+
+// Enqueuer is an interface of containers what accept elements one by one
+type Enqueuer[T any] interface {
+	Enqueue(T)
+}
+
+// EnqueuerFunc adapts a function to the Enqueuer interface (for example StackImpl.Push)
+type EnqueuerFunc[T any] func(T)
+
+// Enqueue calls the function with value.
+func (f EnqueuerFunc[T]) Enqueue(value T) {
+	f(value)
+}
+
+// Dequeuer is an interface of containers what elements can be taken one by one
+type Dequeuer[T any] interface {
+	TryDequeue() (T, bool)
+}
+
+// DequeuerFunc adapts a function to the Dequeuer interface (for example StackImpl.TryPop)
+type DequeuerFunc[T any] func() (T, bool)
+
+// TryDequeue calls the function.
+func (f DequeuerFunc[T]) TryDequeue() (T, bool) {
+	return f()
+}
+
+// Buffer is an interface of containers what can be used as Pipe buffer
+type Buffer[T any] interface {
+	Enqueuer[T]
+	Dequeuer[T]
+}
+
+// PriorityEnqueuer adapts the priority queue to the Enqueuer of priority queue elements.
+//
+// pq: the priority queue to be fed.
+// Returns an Enqueuer what enqueues elements with their priorities.
+func PriorityEnqueuer[T any](pq PriorityQueue[T]) EnqueuerFunc[PriorityQueueElement[T]] {
+	return func(element PriorityQueueElement[T]) {
+		pq.Enqueue(element.Priority, element.Value)
+	}
+}
+
+// FromChan reads values from the channel and enqueues them to the container until the
+// channel is closed or the context is done. The function blocks, run it in a goroutine
+// if needed.
+//
+// ctx: the context to stop reading.
+// in: the source channel.
+// q: the container to be fed.
+// Returns nil when the channel is closed or the context error.
+func FromChan[T any](ctx context.Context, in <-chan T, q Enqueuer[T]) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case value, ok := <-in:
+			if !ok {
+				return nil
+			}
+			q.Enqueue(value)
+		}
+	}
+}
+
+// ToChan drains the container to the returned channel. The channel is closed when the
+// container becomes empty or the context is done. The elements are taken in the
+// container order, so the priority queue is drained by priorities. The context is
+// checked before each element is taken. The element already taken from the container
+// while waiting for the consumer is passed to undelivered if the context is done meanwhile.
+//
+// ctx: the context to stop draining.
+// q: the container to be drained.
+// undelivered: the function what takes back the undelivered element, it is called from the
+// draining goroutine before the channel is closed. Nil drops the element.
+// Returns a channel of the container elements.
+func ToChan[T any](ctx context.Context, q Dequeuer[T], undelivered func(T)) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		defer close(out)
+
+		for ctx.Err() == nil {
+			value, ok := q.TryDequeue()
+			if !ok {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				if undelivered != nil {
+					undelivered(value)
+				}
+				return
+			case out <- value:
+			}
+		}
+	}()
+
+	return out
+}
+
+// Pipe connects the input channel with the returned channel through the buffer. Producers
+// never block on slow consumers, values wait in the buffer. The output is closed when the
+// input is closed and the buffer is drained, or when the context is done. One value may
+// already be taken from the buffer while waiting for the consumer, it is passed to undelivered
+// if the context is done meanwhile. The values left in the buffer stay there.
+//
+// ctx: the context to stop the pipe.
+// in: the source channel.
+// buffer: the container to hold values in flight.
+// undelivered: the function what takes back the undelivered value, it is called from the pipe
+// goroutine before the output is closed. Nil drops the value.
+// Returns a channel of the buffered values.
+func Pipe[T any](ctx context.Context, in <-chan T, buffer Buffer[T], undelivered func(T)) <-chan T {
+	return pipe(ctx, in, buffer.Enqueue, buffer.TryDequeue, undelivered)
+}
+
+// PipePriority connects the input channel with the returned channel through the priority
+// queue, so buffered values are emitted by priorities. One value may already be taken from
+// the queue while waiting for the consumer, it is passed to undelivered if the context is done
+// meanwhile.
+//
+// ctx: the context to stop the pipe.
+// in: the source channel of elements with priorities.
+// pq: the priority queue to hold values in flight.
+// undelivered: the function what takes back the undelivered value, see Pipe.
+// Returns a channel of the buffered values.
+func PipePriority[T any](ctx context.Context, in <-chan PriorityQueueElement[T], pq *PriorityQueueImpl[T], undelivered func(T)) <-chan T {
+	return pipe(ctx, in, PriorityEnqueuer[T](pq).Enqueue, pq.TryDequeue, undelivered)
+}
+
+// pipe moves values from in to the returned channel through the container. The context is
+// checked before each value is taken from the container, the value taken but not sent is
+// passed to undelivered.
+func pipe[In, Out any](ctx context.Context, in <-chan In, enqueue func(In), tryDequeue func() (Out, bool), undelivered func(Out)) <-chan Out {
+	out := make(chan Out)
+
+	go func() {
+		defer close(out)
+
+		var pending Out
+		hasPending := false
+		defer func() {
+			if hasPending && undelivered != nil {
+				undelivered(pending)
+			}
+		}()
+
+		for ctx.Err() == nil {
+			if !hasPending {
+				pending, hasPending = tryDequeue()
+			}
+			if in == nil && !hasPending {
+				return
+			}
+
+			var send chan<- Out
+			if hasPending {
+				send = out
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				enqueue(value)
+			case send <- pending:
+				var zero Out
+				pending = zero
+				hasPending = false
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,170 @@
+package vector
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect[T any](ch <-chan T) (ret []T) {
+	for value := range ch {
+		ret = append(ret, value)
+	}
+	return
+}
+
+func TestFromChan(t *testing.T) {
+	in := make(chan int)
+	q := NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{})
+
+	go func() {
+		for i := 0; i < 5; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	assert.NoError(t, FromChan[int](context.Background(), in, q))
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, q.Vector.Data())
+}
+
+func TestFromChan_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	stack := NewStack[int]()
+	assert.ErrorIs(t, FromChan[int](ctx, make(chan int), EnqueuerFunc[int](stack.Push)), context.Canceled)
+}
+
+func TestToChan(t *testing.T) {
+	q := NewQueue[int](QueueKindFifo)
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, collect(ToChan[int](context.Background(), q, nil)))
+	assert.True(t, q.Empty())
+
+	stack := NewStack[int]()
+	stack.Push(1)
+	stack.Push(2)
+	assert.Equal(t, []int{2, 1}, collect(ToChan[int](context.Background(), DequeuerFunc[int](stack.TryPop), nil)))
+
+	pq := NewPriorityQueue[string]()
+	pq.Enqueue(0, "low")
+	pq.Enqueue(2, "high")
+	pq.Enqueue(1, "middle")
+	assert.Equal(t, []string{"high", "middle", "low"}, collect(ToChan[string](context.Background(), pq, nil)))
+}
+
+func TestToChan_Canceled(t *testing.T) {
+	q := NewQueue[int](QueueKindFifo)
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var undelivered []int
+	out := ToChan[int](ctx, q, func(value int) {
+		undelivered = append(undelivered, value)
+	})
+	assert.Equal(t, 0, <-out)
+	cancel()
+
+	// The channel must be closed after cancellation.
+	delivered := 0
+	for range out {
+		delivered++
+	}
+
+	// The element taken while waiting for the consumer is given back, nothing is lost.
+	assert.LessOrEqual(t, len(undelivered), 1)
+	assert.Equal(t, 5, 1+delivered+len(undelivered)+q.Len())
+}
+
+func TestToChan_CanceledBefore(t *testing.T) {
+	q := NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{})
+	q.Enqueue(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range ToChan[int](ctx, q, nil) {
+	}
+	// The canceled context stops draining before the element is taken.
+	assert.Equal(t, 1, q.Len())
+
+	for range Pipe[int](ctx, nil, q, nil) {
+	}
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestPipe(t *testing.T) {
+	in := make(chan int)
+	out := Pipe[int](context.Background(), in, NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{}), nil)
+
+	// The pipe buffers values, so the producer is not blocked by the absent consumer.
+	for i := 0; i < 100; i++ {
+		in <- i
+	}
+	close(in)
+
+	values := collect(out)
+	assert.Len(t, values, 100)
+	for i, value := range values {
+		assert.Equal(t, i, value)
+	}
+}
+
+func TestPipePriority(t *testing.T) {
+	in := make(chan PriorityQueueElement[string], 3)
+	in <- PriorityQueueElement[string]{Priority: 0, Value: "low"}
+	in <- PriorityQueueElement[string]{Priority: 2, Value: "high"}
+	in <- PriorityQueueElement[string]{Priority: 1, Value: "middle"}
+	close(in)
+
+	pq := NewPriorityQueue[string]()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, FromChan[PriorityQueueElement[string]](context.Background(), in, PriorityEnqueuer[string](pq)))
+	}()
+	wg.Wait()
+
+	assert.Equal(t, []string{"high", "middle", "low"}, collect(PipePriority[string](context.Background(), nil, pq.WithLocker(&sync.Mutex{}), nil)))
+}
+
+func TestPipe_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Pipe[int](ctx, make(chan int), NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{}), nil)
+	cancel()
+
+	for range out {
+	}
+
+	// The value taken from the buffer but not sent is given back.
+	in := make(chan int, 3)
+	in <- 1
+	in <- 2
+	in <- 3
+	close(in)
+	buffer := NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{})
+	ctx, cancel = context.WithCancel(context.Background())
+	var undelivered []int
+	out = Pipe[int](ctx, in, buffer, func(value int) {
+		undelivered = append(undelivered, value)
+	})
+	assert.Equal(t, 1, <-out)
+	cancel()
+
+	var delivered []int
+	for value := range out {
+		delivered = append(delivered, value)
+	}
+	all := append(append([]int{1}, delivered...), undelivered...)
+	for value, ok := buffer.TryDequeue(); ok; value, ok = buffer.TryDequeue() {
+		all = append(all, value)
+	}
+	assert.ElementsMatch(t, []int{1, 2, 3}, all)
+}
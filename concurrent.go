@@ -0,0 +1,466 @@
+package vector
+
+import (
+	"hash/maphash"
+	"math"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Hashable is a types restriction interface for the types what the concurrent containers hash
+// without the explicit hash function, the named types are included
+type Hashable interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// underlying reinterprets value as its underlying type U, the types must have the same underlying type
+func underlying[U, T any](value T) U {
+	return *(*U)(unsafe.Pointer(&value))
+}
+
+// basicHash returns the hash function of the hashable type, the function is selected by the
+// underlying type kind once
+func basicHash[T Hashable]() HashFunc[T] {
+	seed := maphash.MakeSeed()
+	var zero T
+	switch reflect.TypeOf(zero).Kind() {
+	case reflect.String:
+		return func(value T) uint64 { return maphash.String(seed, underlying[string](value)) }
+	case reflect.Bool:
+		return func(value T) uint64 {
+			if underlying[bool](value) {
+				return mix64(1)
+			}
+			return 0
+		}
+	case reflect.Int:
+		return func(value T) uint64 { return mix64(uint64(underlying[int](value))) }
+	case reflect.Int8:
+		return func(value T) uint64 { return mix64(uint64(underlying[int8](value))) }
+	case reflect.Int16:
+		return func(value T) uint64 { return mix64(uint64(underlying[int16](value))) }
+	case reflect.Int32:
+		return func(value T) uint64 { return mix64(uint64(underlying[int32](value))) }
+	case reflect.Int64:
+		return func(value T) uint64 { return mix64(uint64(underlying[int64](value))) }
+	case reflect.Uint:
+		return func(value T) uint64 { return mix64(uint64(underlying[uint](value))) }
+	case reflect.Uint8:
+		return func(value T) uint64 { return mix64(uint64(underlying[uint8](value))) }
+	case reflect.Uint16:
+		return func(value T) uint64 { return mix64(uint64(underlying[uint16](value))) }
+	case reflect.Uint32:
+		return func(value T) uint64 { return mix64(uint64(underlying[uint32](value))) }
+	case reflect.Uint64:
+		return func(value T) uint64 { return mix64(underlying[uint64](value)) }
+	case reflect.Uintptr:
+		return func(value T) uint64 { return mix64(uint64(underlying[uintptr](value))) }
+	case reflect.Float32:
+		return func(value T) uint64 { return hashFloat(float64(underlying[float32](value))) }
+	default:
+		return func(value T) uint64 { return hashFloat(underlying[float64](value)) }
+	}
+}
+
+// hashFloat hashes a float so that both zeroes have the same hash
+func hashFloat(value float64) uint64 {
+	if value == 0 {
+		return 0
+	}
+	return mix64(math.Float64bits(value))
+}
+
+// mix64 is the splitmix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// shardsCount rounds the shards count up to the power of two, the non positive count
+// is replaced by four shards per processor
+func shardsCount(count int) int {
+	if count <= 0 {
+		count = 4 * runtime.GOMAXPROCS(0)
+	}
+	ret := 1
+	for ret < count {
+		ret <<= 1
+	}
+	return ret
+}
+
+// mapShard is a part of the sharded map guarded by its own lock
+type mapShard[K comparable, V any] struct {
+	sync.RWMutex
+	data map[K]V
+	size atomic.Int64
+}
+
+// shardedMap is a map split to the independently locked shards
+type shardedMap[K comparable, V any] struct {
+	shards []*mapShard[K, V]
+	mask   uint64
+	hash   HashFunc[K]
+}
+
+// newShardedMap creates a map of count shards, hash selects the key shard
+func newShardedMap[K comparable, V any](count int, hash HashFunc[K]) shardedMap[K, V] {
+	count = shardsCount(count)
+	ret := shardedMap[K, V]{
+		shards: make([]*mapShard[K, V], count),
+		mask:   uint64(count - 1),
+		hash:   hash,
+	}
+	for index := range ret.shards {
+		ret.shards[index] = &mapShard[K, V]{data: make(map[K]V)}
+	}
+	return ret
+}
+
+// shard returns the shard of the key
+func (m *shardedMap[K, V]) shard(key K) *mapShard[K, V] {
+	return m.shards[m.hash(key)&m.mask]
+}
+
+// lockAll locks all shards for reading and returns the unlock function
+func (m *shardedMap[K, V]) lockAll() func() {
+	for _, shard := range m.shards {
+		shard.RLock()
+	}
+	return func() {
+		for _, shard := range m.shards {
+			shard.RUnlock()
+		}
+	}
+}
+
+// len returns the count of entries, the shards are not locked so the result is an estimate
+// if the map is modified concurrently
+func (m *shardedMap[K, V]) len() (ret int) {
+	for _, shard := range m.shards {
+		ret += int(shard.size.Load())
+	}
+	return
+}
+
+// snapshot returns the copy of all entries made at a single point in time
+func (m *shardedMap[K, V]) snapshot() map[K]V {
+	defer m.lockAll()()
+
+	ret := make(map[K]V, m.len())
+	for _, shard := range m.shards {
+		for key, value := range shard.data {
+			ret[key] = value
+		}
+	}
+	return ret
+}
+
+// load returns the value of the key
+func (m *shardedMap[K, V]) load(key K) (value V, ok bool) {
+	shard := m.shard(key)
+	shard.RLock()
+	defer shard.RUnlock()
+
+	value, ok = shard.data[key]
+	return
+}
+
+// compute atomically replaces the key entry by the result of update. The update takes the
+// current value and the presence flag and returns the new value and the presence flag.
+func (m *shardedMap[K, V]) compute(key K, update func(value V, ok bool) (V, bool)) (V, bool) {
+	shard := m.shard(key)
+	shard.Lock()
+	defer shard.Unlock()
+
+	value, ok := shard.data[key]
+	newValue, keep := update(value, ok)
+	switch {
+	case keep:
+		shard.data[key] = newValue
+		if !ok {
+			shard.size.Add(1)
+		}
+	case ok:
+		delete(shard.data, key)
+		shard.size.Add(-1)
+	}
+	return newValue, keep
+}
+
+// ConcurrentSetImpl is a set of comparable elements split to the independently locked shards.
+// It has no single lock, so the operations on different shards do not contend. The elements
+// order of Data and Range is unspecified.
+type ConcurrentSetImpl[T comparable] struct {
+	shardedMap[T, struct{}]
+}
+
+// NewConcurrentSet returns a new empty ConcurrentSetImpl instance of the hashable elements
+// (strings, booleans, numbers and the named types of them). Use NewConcurrentHashFuncSet for
+// other types.
+//
+// shards: the count of shards, it is rounded up to the power of two. The non positive count
+// selects four shards per processor.
+// Returns a pointer to the new ConcurrentSetImpl.
+func NewConcurrentSet[T Hashable](shards int) *ConcurrentSetImpl[T] {
+	return &ConcurrentSetImpl[T]{
+		shardedMap: newShardedMap[T, struct{}](shards, basicHash[T]()),
+	}
+}
+
+// NewConcurrentHashFuncSet returns a new empty ConcurrentSetImpl instance what uses the hash
+// function to select shards.
+//
+// shards: the count of shards, it is rounded up to the power of two. The non positive count
+// selects four shards per processor.
+// hash: the function used to hash elements, equal elements must have equal hashes.
+// Returns a pointer to the new ConcurrentSetImpl.
+func NewConcurrentHashFuncSet[T comparable](shards int, hash HashFunc[T]) *ConcurrentSetImpl[T] {
+	return &ConcurrentSetImpl[T]{
+		shardedMap: newShardedMap[T, struct{}](shards, hash),
+	}
+}
+
+// Len returns the count of the set elements. It is an estimate if the set is modified concurrently.
+func (s *ConcurrentSetImpl[T]) Len() int {
+	return s.len()
+}
+
+// Empty checks if the set is empty.
+func (s *ConcurrentSetImpl[T]) Empty() bool {
+	return s.len() == 0
+}
+
+// Data returns the snapshot of the set elements.
+func (s *ConcurrentSetImpl[T]) Data() []T {
+	snapshot := s.snapshot()
+	ret := make([]T, 0, len(snapshot))
+	for value := range snapshot {
+		ret = append(ret, value)
+	}
+	return ret
+}
+
+// Add elements to the set. Every element is added atomically.
+//
+// Returns the count of added elements.
+func (s *ConcurrentSetImpl[T]) Add(values ...T) (count int) {
+	for _, value := range values {
+		s.compute(value, func(_ struct{}, ok bool) (struct{}, bool) {
+			if !ok {
+				count++
+			}
+			return struct{}{}, true
+		})
+	}
+	return
+}
+
+// Remove elements from the set. Every element is removed atomically.
+//
+// Returns the count of removed elements.
+func (s *ConcurrentSetImpl[T]) Remove(values ...T) (count int) {
+	for _, value := range values {
+		s.compute(value, func(_ struct{}, ok bool) (struct{}, bool) {
+			if ok {
+				count++
+			}
+			return struct{}{}, false
+		})
+	}
+	return
+}
+
+// Has checks if the set contains the value.
+func (s *ConcurrentSetImpl[T]) Has(value T) bool {
+	_, ok := s.load(value)
+	return ok
+}
+
+// HasAny checks if the set contains any of the values.
+func (s *ConcurrentSetImpl[T]) HasAny(values ...T) bool {
+	for _, value := range values {
+		if s.Has(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll checks if the set contains all of the values.
+func (s *ConcurrentSetImpl[T]) HasAll(values ...T) bool {
+	for _, value := range values {
+		if !s.Has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Range enumerates the snapshot of set elements, so the callback may modify the set.
+// If the callback returns an error, the iteration stops and returns the error.
+func (s *ConcurrentSetImpl[T]) Range(callback func(index int, value T) error) error {
+	for index, value := range s.Data() {
+		if err := callback(index, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConcurrentMapImpl is a map split to the independently locked shards. It has no single
+// lock, so the operations on different shards do not contend. The entries order of Range,
+// Keys and Values is unspecified.
+type ConcurrentMapImpl[K comparable, V any] struct {
+	shardedMap[K, V]
+}
+
+// NewConcurrentMap returns a new empty ConcurrentMapImpl instance of the hashable keys
+// (strings, booleans, numbers and the named types of them). Use NewConcurrentHashFuncMap for
+// other types.
+//
+// shards: the count of shards, it is rounded up to the power of two. The non positive count
+// selects four shards per processor.
+// Returns a pointer to the new ConcurrentMapImpl.
+func NewConcurrentMap[K Hashable, V any](shards int) *ConcurrentMapImpl[K, V] {
+	return &ConcurrentMapImpl[K, V]{
+		shardedMap: newShardedMap[K, V](shards, basicHash[K]()),
+	}
+}
+
+// NewConcurrentHashFuncMap returns a new empty ConcurrentMapImpl instance what uses the hash
+// function to select shards.
+//
+// shards: the count of shards, it is rounded up to the power of two. The non positive count
+// selects four shards per processor.
+// hash: the function used to hash keys, equal keys must have equal hashes.
+// Returns a pointer to the new ConcurrentMapImpl.
+func NewConcurrentHashFuncMap[K comparable, V any](shards int, hash HashFunc[K]) *ConcurrentMapImpl[K, V] {
+	return &ConcurrentMapImpl[K, V]{
+		shardedMap: newShardedMap[K, V](shards, hash),
+	}
+}
+
+// Len returns the count of map entries. It is an estimate if the map is modified concurrently.
+func (m *ConcurrentMapImpl[K, V]) Len() int {
+	return m.len()
+}
+
+// Empty checks if the map is empty.
+func (m *ConcurrentMapImpl[K, V]) Empty() bool {
+	return m.len() == 0
+}
+
+// Load returns the value for the key.
+//
+// Returns the value and true, or zero value and false if there is no such key.
+func (m *ConcurrentMapImpl[K, V]) Load(key K) (V, bool) {
+	return m.load(key)
+}
+
+// Store sets the value for the key.
+func (m *ConcurrentMapImpl[K, V]) Store(key K, value V) {
+	m.compute(key, func(V, bool) (V, bool) {
+		return value, true
+	})
+}
+
+// LoadOrStore returns the existing value for the key if present. Otherwise, it stores
+// and returns the given value.
+//
+// Returns the actual value and true if the value was loaded, false if stored.
+func (m *ConcurrentMapImpl[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	actual, _ = m.compute(key, func(old V, ok bool) (V, bool) {
+		loaded = ok
+		if ok {
+			return old, true
+		}
+		return value, true
+	})
+	return
+}
+
+// LoadAndDelete deletes the value for the key.
+//
+// Returns the previous value and true, or zero value and false if there was no such key.
+func (m *ConcurrentMapImpl[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	m.compute(key, func(old V, ok bool) (V, bool) {
+		value, loaded = old, ok
+		return old, false
+	})
+	return
+}
+
+// Swap stores the value for the key.
+//
+// Returns the previous value and true, or zero value and false if there was no such key.
+func (m *ConcurrentMapImpl[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	m.compute(key, func(old V, ok bool) (V, bool) {
+		previous, loaded = old, ok
+		return value, true
+	})
+	return
+}
+
+// Delete removes the key entry from the map.
+//
+// Returns true if the entry was removed.
+func (m *ConcurrentMapImpl[K, V]) Delete(key K) bool {
+	_, loaded := m.LoadAndDelete(key)
+	return loaded
+}
+
+// Compute atomically updates the key entry. The update takes the current value and the
+// presence flag and returns the new value and whether the entry has to be kept; the entry
+// is deleted if not kept. The update must not access the map.
+//
+// Returns the new value and whether the entry is present.
+func (m *ConcurrentMapImpl[K, V]) Compute(key K, update func(value V, ok bool) (V, bool)) (V, bool) {
+	return m.compute(key, update)
+}
+
+// Snapshot returns the copy of all map entries made at a single point in time.
+func (m *ConcurrentMapImpl[K, V]) Snapshot() map[K]V {
+	return m.snapshot()
+}
+
+// Keys returns the snapshot of map keys.
+func (m *ConcurrentMapImpl[K, V]) Keys() []K {
+	snapshot := m.snapshot()
+	ret := make([]K, 0, len(snapshot))
+	for key := range snapshot {
+		ret = append(ret, key)
+	}
+	return ret
+}
+
+// Values returns the snapshot of map values.
+func (m *ConcurrentMapImpl[K, V]) Values() []V {
+	snapshot := m.snapshot()
+	ret := make([]V, 0, len(snapshot))
+	for _, value := range snapshot {
+		ret = append(ret, value)
+	}
+	return ret
+}
+
+// Range enumerates the snapshot of map entries, so the callback may modify the map.
+// If the callback returns an error, the iteration stops and returns the error.
+func (m *ConcurrentMapImpl[K, V]) Range(callback func(key K, value V) error) error {
+	for key, value := range m.snapshot() {
+		if err := callback(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package vector
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	var s Set[int, CompareFunc[int]] = NewConcurrentSet[int](3)
+	assert.True(t, s.Empty())
+
+	assert.Equal(t, 3, s.Add(1, 2, 3, 2))
+	assert.True(t, s.Has(2))
+	assert.True(t, s.HasAny(5, 3))
+	assert.False(t, s.HasAll(1, 5))
+	assert.Equal(t, 2, s.Remove(1, 3, 5))
+
+	assert.Equal(t, []int{2}, s.Data())
+
+	count := 0
+	assert.NoError(t, s.Range(func(_ int, value int) error {
+		count++
+		// The callback is called with the snapshot, so the set may be modified.
+		s.Add(value + 1)
+		return nil
+	}))
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int{2, 3}, sorted(s.Data()))
+
+	err := errors.New("stop")
+	assert.ErrorIs(t, s.Range(func(int, int) error { return err }), err)
+}
+
+func TestConcurrentSet_Hash(t *testing.T) {
+	type key struct {
+		a int
+		b string
+	}
+	// The struct elements require the explicit hash function.
+	s := NewConcurrentHashFuncSet[key](0, func(k key) uint64 { return uint64(k.a) })
+	assert.Equal(t, 2, s.Add(key{1, "a"}, key{2, "b"}, key{1, "a"}))
+	assert.True(t, s.Has(key{2, "b"}))
+	assert.False(t, s.Has(key{2, "a"}))
+
+	m := NewConcurrentHashFuncMap[key, int](0, func(k key) uint64 { return uint64(k.a) })
+	m.Store(key{1, "a"}, 1)
+	value, ok := m.Load(key{1, "a"})
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	floats := NewConcurrentSet[float64](8)
+	zero := 0.0
+	floats.Add(zero)
+	assert.True(t, floats.Has(-zero))
+
+	bools := NewConcurrentSet[bool](2)
+	assert.Equal(t, 2, bools.Add(true, false, true))
+
+	// The named types of the basic types are hashed as their underlying types.
+	type id int
+	type name string
+	ids := NewConcurrentSet[id](4)
+	assert.Equal(t, 3, ids.Add(1, 2, 3, 2))
+	assert.True(t, ids.Has(3))
+	names := NewConcurrentMap[name, id](4)
+	names.Store("a", 1)
+	loaded, ok := names.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, id(1), loaded)
+}
+
+func TestConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap[string, int](4)
+	assert.True(t, m.Empty())
+
+	m.Store("a", 1)
+	value, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	actual, loaded := m.LoadOrStore("a", 10)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+	actual, loaded = m.LoadOrStore("b", 2)
+	assert.False(t, loaded)
+	assert.Equal(t, 2, actual)
+
+	previous, loaded := m.Swap("b", 20)
+	assert.True(t, loaded)
+	assert.Equal(t, 2, previous)
+
+	value, ok = m.Compute("c", func(old int, ok bool) (int, bool) {
+		assert.False(t, ok)
+		return old + 3, true
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+	_, ok = m.Compute("c", func(int, bool) (int, bool) { return 0, false })
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 20}, m.Snapshot())
+	assert.Equal(t, 2, m.Len())
+
+	keys := m.Keys()
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+	assert.Equal(t, []int{1, 20}, sorted(m.Values()))
+
+	value, loaded = m.LoadAndDelete("a")
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+	assert.False(t, m.Delete("a"))
+	assert.True(t, m.Delete("b"))
+	assert.True(t, m.Empty())
+
+	m.Store("x", 1)
+	assert.NoError(t, m.Range(func(key string, value int) error {
+		m.Store(key+key, value)
+		return nil
+	}))
+	assert.Equal(t, map[string]int{"x": 1, "xx": 1}, m.Snapshot())
+}
+
+func TestConcurrentMap_Race(t *testing.T) {
+	const (
+		workers   = 8
+		perWorker = 1000
+	)
+
+	m := NewConcurrentMap[int, int](0)
+	s := NewConcurrentSet[int](0)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				m.Compute(i, func(old int, _ bool) (int, bool) {
+					return old + 1, true
+				})
+				s.Add(worker*perWorker + i)
+				if i%100 == 0 {
+					_ = m.Snapshot()
+					_ = s.Len()
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	assert.Equal(t, perWorker, m.Len())
+	assert.NoError(t, m.Range(func(_ int, value int) error {
+		assert.Equal(t, workers, value)
+		return nil
+	}))
+	assert.Equal(t, workers*perWorker, s.Len())
+}
+
+func BenchmarkConcurrentSet(b *testing.B) {
+	s := NewConcurrentSet[int](0)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Add(i % 1024)
+			s.Has(i % 512)
+			i++
+		}
+	})
+}
+
+func BenchmarkLockedSet(b *testing.B) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int]).WithLocker(&sync.Mutex{})
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Add(i % 1024)
+			s.Has(i % 512)
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentMap(b *testing.B) {
+	m := NewConcurrentMap[int, int](0)
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.LoadOrStore(i%1024, i)
+			m.Load(i % 512)
+			i++
+		}
+	})
+}
@@ -0,0 +1,491 @@
+package vector
+
+import "sync"
+
+// HashFunc is a function that hashes a value
+type HashFunc[T any] func(T) uint64
+
+// EqualFunc is a function that checks two values equality
+type EqualFunc[T any] func(T, T) bool
+
+// lockPair locks lhs and rhs in the order of their ids and returns the unlock function. The same
+// locker holder is locked once, so the operations of the set with itself do not deadlock. The
+// lockers are told apart by the holder, so the non comparable lockers are supported.
+func lockPair(lhs, rhs *orderLocker) func() {
+	if lhs == rhs {
+		lhs.Lock()
+		return lhs.Unlock
+	}
+	if rhs.id < lhs.id {
+		lhs, rhs = rhs, lhs
+	}
+	lhs.Lock()
+	rhs.Lock()
+	return func() {
+		rhs.Unlock()
+		lhs.Unlock()
+	}
+}
+
+// HashSetImpl is an implementation of set for comparable types backed by the builtin map.
+// The elements order of Data and Range is unspecified.
+type HashSetImpl[T comparable] struct {
+	locker *orderLocker
+	data   map[T]struct{}
+}
+
+// MakeHashSet returns a new empty HashSetImpl.
+func MakeHashSet[T comparable]() HashSetImpl[T] {
+	return HashSetImpl[T]{
+		locker: newOrderLocker(),
+		data:   make(map[T]struct{}),
+	}
+}
+
+// NewHashSet returns a new empty HashSetImpl instance.
+func NewHashSet[T comparable]() *HashSetImpl[T] {
+	ret := MakeHashSet[T]()
+	return &ret
+}
+
+// WithLocker sets the locker to be used by set and returns the updated set.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the set.
+// returns: a pointer to the updated set.
+func (s *HashSetImpl[T]) WithLocker(locker sync.Locker) *HashSetImpl[T] {
+	s.locker.Locker = locker
+	return s
+}
+
+// Locker returns the locker to be used by set
+func (s *HashSetImpl[T]) Locker() sync.Locker {
+	return s.locker.Locker
+}
+
+// xdata returns a copy of the set data
+func (s *HashSetImpl[T]) xdata() []T {
+	ret := make([]T, 0, len(s.data))
+	for value := range s.data {
+		ret = append(ret, value)
+	}
+	return ret
+}
+
+// Data returns a copy of the set data.
+func (s *HashSetImpl[T]) Data() []T {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.xdata()
+}
+
+// Len returns the count of the set elements.
+func (s *HashSetImpl[T]) Len() int {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return len(s.data)
+}
+
+// Empty checks if the set is empty.
+func (s *HashSetImpl[T]) Empty() bool {
+	return s.Len() == 0
+}
+
+// Add elements to the set.
+func (s *HashSetImpl[T]) add(values ...T) (count int) {
+	for _, v := range values {
+		if _, ok := s.data[v]; !ok {
+			s.data[v] = struct{}{}
+			count++
+		}
+	}
+	return
+}
+
+// Add elements to the set.
+func (s *HashSetImpl[T]) Add(values ...T) (count int) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.add(values...)
+}
+
+// Remove elements from the set.
+func (s *HashSetImpl[T]) remove(values ...T) (count int) {
+	for _, v := range values {
+		if _, ok := s.data[v]; ok {
+			delete(s.data, v)
+			count++
+		}
+	}
+	return
+}
+
+// Remove elements from the set.
+func (s *HashSetImpl[T]) Remove(values ...T) (count int) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.remove(values...)
+}
+
+// Range enumerates set elements.
+func (s *HashSetImpl[T]) Range(callback func(index int, value T) error) error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	index := 0
+	for value := range s.data {
+		if err := callback(index, value); err != nil {
+			return err
+		}
+		index++
+	}
+	return nil
+}
+
+// has checks if the set contains the given value
+func (s *HashSetImpl[T]) has(value T) bool {
+	_, ok := s.data[value]
+	return ok
+}
+
+// Has checks if the set contains the given value.
+func (s *HashSetImpl[T]) Has(value T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.has(value)
+}
+
+// HasAny checks if the set contains any of the given values.
+func (s *HashSetImpl[T]) HasAny(values ...T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	for _, v := range values {
+		if s.has(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll checks if the set contains all of the given values.
+func (s *HashSetImpl[T]) HasAll(values ...T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	for _, v := range values {
+		if !s.has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// lockBoth locks the set and rhs as lockPair does and returns the unlock function
+func (s *HashSetImpl[T]) lockBoth(rhs *HashSetImpl[T]) func() {
+	return lockPair(s.locker, rhs.locker)
+}
+
+// Union constructs a new set of the elements what are available in any of sets.
+func (s *HashSetImpl[T]) Union(rhs *HashSetImpl[T]) *HashSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	ret := NewHashSet[T]()
+	for v := range s.data {
+		ret.data[v] = struct{}{}
+	}
+	for v := range rhs.data {
+		ret.data[v] = struct{}{}
+	}
+	return ret
+}
+
+// LeftDifference constructs a new set of the elements what are available in set and not available in rhs.
+func (s *HashSetImpl[T]) LeftDifference(rhs *HashSetImpl[T]) *HashSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	ret := NewHashSet[T]()
+	for v := range s.data {
+		if !rhs.has(v) {
+			ret.data[v] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// RightDifference constructs a new set of the elements what are available in rhs and not available in set.
+func (s *HashSetImpl[T]) RightDifference(rhs *HashSetImpl[T]) *HashSetImpl[T] {
+	return rhs.LeftDifference(s)
+}
+
+// Intersection constructs a new set of the elements what are available in both sets.
+func (s *HashSetImpl[T]) Intersection(rhs *HashSetImpl[T]) *HashSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	small, large := s, rhs
+	if len(small.data) > len(large.data) {
+		small, large = large, small
+	}
+
+	ret := NewHashSet[T]()
+	for v := range small.data {
+		if large.has(v) {
+			ret.data[v] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// HashFuncSetImpl is an implementation of set for any types what uses custom hash and
+// equal functions. The elements order of Data and Range is unspecified.
+type HashFuncSetImpl[T any] struct {
+	locker *orderLocker
+	hash   HashFunc[T]
+	equal  EqualFunc[T]
+	data   map[uint64][]T
+	count  int
+}
+
+// MakeHashFuncSet returns a new empty HashFuncSetImpl with the given hash and equal functions.
+//
+// hash: the function to hash elements, equal elements must have equal hashes.
+// equal: the function to check elements equality.
+func MakeHashFuncSet[T any](hash HashFunc[T], equal EqualFunc[T]) HashFuncSetImpl[T] {
+	return HashFuncSetImpl[T]{
+		locker: newOrderLocker(),
+		hash:   hash,
+		equal:  equal,
+		data:   make(map[uint64][]T),
+	}
+}
+
+// NewHashFuncSet returns a new empty HashFuncSetImpl instance with the given hash and equal functions.
+//
+// hash: the function to hash elements, equal elements must have equal hashes.
+// equal: the function to check elements equality.
+func NewHashFuncSet[T any](hash HashFunc[T], equal EqualFunc[T]) *HashFuncSetImpl[T] {
+	ret := MakeHashFuncSet(hash, equal)
+	return &ret
+}
+
+// WithLocker sets the locker to be used by set and returns the updated set.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the set.
+// returns: a pointer to the updated set.
+func (s *HashFuncSetImpl[T]) WithLocker(locker sync.Locker) *HashFuncSetImpl[T] {
+	s.locker.Locker = locker
+	return s
+}
+
+// Locker returns the locker to be used by set
+func (s *HashFuncSetImpl[T]) Locker() sync.Locker {
+	return s.locker.Locker
+}
+
+// xdata returns a copy of the set data
+func (s *HashFuncSetImpl[T]) xdata() []T {
+	ret := make([]T, 0, s.count)
+	for _, bucket := range s.data {
+		ret = append(ret, bucket...)
+	}
+	return ret
+}
+
+// Data returns a copy of the set data.
+func (s *HashFuncSetImpl[T]) Data() []T {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.xdata()
+}
+
+// Len returns the count of the set elements.
+func (s *HashFuncSetImpl[T]) Len() int {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.count
+}
+
+// Empty checks if the set is empty.
+func (s *HashFuncSetImpl[T]) Empty() bool {
+	return s.Len() == 0
+}
+
+// indexOf returns the hash and the index of value in its bucket or -1
+func (s *HashFuncSetImpl[T]) indexOf(value T) (hash uint64, index int) {
+	hash = s.hash(value)
+	for i, v := range s.data[hash] {
+		if s.equal(v, value) {
+			return hash, i
+		}
+	}
+	return hash, -1
+}
+
+// Add elements to the set.
+func (s *HashFuncSetImpl[T]) add(values ...T) (count int) {
+	for _, v := range values {
+		hash, index := s.indexOf(v)
+		if index == -1 {
+			s.data[hash] = append(s.data[hash], v)
+			s.count++
+			count++
+		}
+	}
+	return
+}
+
+// Add elements to the set.
+func (s *HashFuncSetImpl[T]) Add(values ...T) (count int) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.add(values...)
+}
+
+// Remove elements from the set.
+func (s *HashFuncSetImpl[T]) remove(values ...T) (count int) {
+	for _, v := range values {
+		hash, index := s.indexOf(v)
+		if index == -1 {
+			continue
+		}
+
+		bucket := s.data[hash]
+		if len(bucket) == 1 {
+			delete(s.data, hash)
+		} else {
+			rest := make([]T, 0, len(bucket)-1)
+			rest = append(rest, bucket[:index]...)
+			s.data[hash] = append(rest, bucket[index+1:]...)
+		}
+		s.count--
+		count++
+	}
+	return
+}
+
+// Remove elements from the set.
+func (s *HashFuncSetImpl[T]) Remove(values ...T) (count int) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.remove(values...)
+}
+
+// Range enumerates set elements.
+func (s *HashFuncSetImpl[T]) Range(callback func(index int, value T) error) error {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	index := 0
+	for _, bucket := range s.data {
+		for _, value := range bucket {
+			if err := callback(index, value); err != nil {
+				return err
+			}
+			index++
+		}
+	}
+	return nil
+}
+
+// has checks if the set contains the given value
+func (s *HashFuncSetImpl[T]) has(value T) bool {
+	_, index := s.indexOf(value)
+	return index != -1
+}
+
+// Has checks if the set contains the given value.
+func (s *HashFuncSetImpl[T]) Has(value T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	return s.has(value)
+}
+
+// HasAny checks if the set contains any of the given values.
+func (s *HashFuncSetImpl[T]) HasAny(values ...T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	for _, v := range values {
+		if s.has(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll checks if the set contains all of the given values.
+func (s *HashFuncSetImpl[T]) HasAll(values ...T) bool {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+
+	for _, v := range values {
+		if !s.has(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// lockBoth locks the set and rhs as lockPair does and returns the unlock function
+func (s *HashFuncSetImpl[T]) lockBoth(rhs *HashFuncSetImpl[T]) func() {
+	return lockPair(s.locker, rhs.locker)
+}
+
+// Union constructs a new set of the elements what are available in any of sets.
+func (s *HashFuncSetImpl[T]) Union(rhs *HashFuncSetImpl[T]) *HashFuncSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	ret := NewHashFuncSet(s.hash, s.equal)
+	ret.add(s.xdata()...)
+	ret.add(rhs.xdata()...)
+	return ret
+}
+
+// LeftDifference constructs a new set of the elements what are available in set and not available in rhs.
+func (s *HashFuncSetImpl[T]) LeftDifference(rhs *HashFuncSetImpl[T]) *HashFuncSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	ret := NewHashFuncSet(s.hash, s.equal)
+	for _, bucket := range s.data {
+		for _, v := range bucket {
+			if !rhs.has(v) {
+				ret.add(v)
+			}
+		}
+	}
+	return ret
+}
+
+// RightDifference constructs a new set of the elements what are available in rhs and not available in set.
+func (s *HashFuncSetImpl[T]) RightDifference(rhs *HashFuncSetImpl[T]) *HashFuncSetImpl[T] {
+	return rhs.LeftDifference(s)
+}
+
+// Intersection constructs a new set of the elements what are available in both sets.
+func (s *HashFuncSetImpl[T]) Intersection(rhs *HashFuncSetImpl[T]) *HashFuncSetImpl[T] {
+	defer s.lockBoth(rhs)()
+
+	small, large := s, rhs
+	if small.count > large.count {
+		small, large = large, small
+	}
+
+	ret := NewHashFuncSet(s.hash, s.equal)
+	for _, bucket := range small.data {
+		for _, v := range bucket {
+			if large.has(v) {
+				ret.add(v)
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,133 @@
+package vector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sorted(values []int) []int {
+	sort.Ints(values)
+	return values
+}
+
+func TestHashSet_Set(t *testing.T) {
+	var s Set[int, CompareFunc[int]] = NewHashSet[int]().WithLocker(&sync.Mutex{})
+
+	assert.True(t, s.Empty())
+	assert.Equal(t, 4, s.Add(12, 223, 3456, 456))
+	assert.Equal(t, 0, s.Add(12, 223, 3456, 456))
+	assert.Equal(t, 2, s.Add(13, 223, 3457, 456))
+	assert.True(t, s.Has(13))
+	assert.False(t, s.Has(14))
+	assert.True(t, s.HasAny(14, 13))
+	assert.False(t, s.HasAll(14, 13))
+	assert.True(t, s.HasAll(12, 13))
+	assert.Equal(t, []int{12, 13, 223, 456, 3456, 3457}, sorted(s.Data()))
+
+	count := 0
+	assert.NoError(t, s.Range(func(index int, value int) error {
+		assert.Equal(t, count, index)
+		count++
+		return nil
+	}))
+	assert.Equal(t, 6, count)
+
+	assert.Equal(t, 2, s.Remove(12, 3456, 1))
+	assert.Equal(t, 4, s.Remove(13, 223, 3457, 456))
+	assert.True(t, s.Empty())
+}
+
+func TestHashSet_Operations(t *testing.T) {
+	s0 := NewHashSet[int]()
+	s1 := NewHashSet[int]()
+
+	s0.Add(1, 2, 3, 4, 5)
+	s1.Add(4, 5, 6, 7, 8)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, sorted(s0.Union(s1).Data()))
+	assert.Equal(t, []int{1, 2, 3}, sorted(s0.LeftDifference(s1).Data()))
+	assert.Equal(t, []int{6, 7, 8}, sorted(s0.RightDifference(s1).Data()))
+	assert.Equal(t, []int{4, 5}, sorted(s0.Intersection(s1).Data()))
+	assert.Equal(t, []int{4, 5}, sorted(s1.Intersection(s0).Data()))
+}
+
+func TestHashSet_Locking(t *testing.T) {
+	s0 := NewHashSet[int]().WithLocker(&sync.Mutex{})
+	s1 := NewHashSet[int]().WithLocker(&sync.Mutex{})
+	s0.Add(1, 2, 3)
+	s1.Add(3, 4)
+
+	// The set operations with itself lock it once.
+	assert.Equal(t, []int{1, 2, 3}, sorted(s0.Union(s0).Data()))
+	assert.Empty(t, s0.LeftDifference(s0).Data())
+	others := NewHashSet[int]().WithLocker(uncomparableLocker{})
+	others.Add(2)
+	assert.Equal(t, []int{2}, sorted(others.Union(others).Data()))
+
+	// The opposite operands order does not deadlock.
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			s0.Union(s1)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			s1.Union(s0)
+		}
+	}()
+	wg.Wait()
+
+	hash := func(v int) uint64 { return uint64(v) }
+	equal := func(lhs, rhs int) bool { return lhs == rhs }
+	fs := NewHashFuncSet[int](hash, equal).WithLocker(&sync.Mutex{})
+	fs.Add(1, 2)
+	assert.Equal(t, []int{1, 2}, sorted(fs.Union(fs).Data()))
+	assert.Equal(t, []int{1, 2}, sorted(fs.Intersection(fs).Data()))
+}
+
+func TestHashFuncSet(t *testing.T) {
+	type key struct {
+		tags []string
+	}
+
+	hash := func(k key) (ret uint64) {
+		for _, c := range strings.Join(k.tags, ",") {
+			ret = ret*31 + uint64(c)
+		}
+		// Poor hash to force collisions.
+		return ret % 2
+	}
+	equal := func(lhs, rhs key) bool {
+		return strings.Join(lhs.tags, ",") == strings.Join(rhs.tags, ",")
+	}
+
+	var s Set[key, CompareFunc[key]] = NewHashFuncSet[key](hash, equal).WithLocker(&sync.Mutex{})
+	assert.Equal(t, 3, s.Add(key{[]string{"a"}}, key{[]string{"b"}}, key{[]string{"a", "b"}}, key{[]string{"a"}}))
+	assert.True(t, s.Has(key{[]string{"a", "b"}}))
+	assert.False(t, s.Has(key{[]string{"b", "a"}}))
+	assert.Len(t, s.Data(), 3)
+	assert.Equal(t, 1, s.Remove(key{[]string{"a"}}))
+	assert.False(t, s.Has(key{[]string{"a"}}))
+	assert.True(t, s.HasAll(key{[]string{"b"}}, key{[]string{"a", "b"}}))
+
+	intHash := func(v int) uint64 { return uint64(v % 3) }
+	intEqual := func(lhs, rhs int) bool { return lhs == rhs }
+	s0 := NewHashFuncSet[int](intHash, intEqual)
+	s1 := NewHashFuncSet[int](intHash, intEqual)
+	s0.Add(1, 2, 3, 4, 5)
+	s1.Add(4, 5, 6, 7, 8)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, sorted(s0.Union(s1).Data()))
+	assert.Equal(t, []int{1, 2, 3}, sorted(s0.LeftDifference(s1).Data()))
+	assert.Equal(t, []int{6, 7, 8}, sorted(s0.RightDifference(s1).Data()))
+	assert.Equal(t, []int{4, 5}, sorted(s0.Intersection(s1).Data()))
+	assert.Equal(t, 8, s0.Union(s1).Len())
+}
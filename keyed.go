@@ -0,0 +1,247 @@
+package vector
+
+import (
+	"sort"
+	"sync"
+)
+
+// KeyFunc is a function what extracts the key of element
+type KeyFunc[T any, K any] func(T) K
+
+// keyed keeps the key extraction and keys compare functions of the keyed containers
+type keyed[K any, T any, C CompareFunc[K]] struct {
+	key     KeyFunc[T, K]
+	compare C
+}
+
+// elementCompare returns the function what compares elements by their keys
+func (k keyed[K, T, C]) elementCompare() CompareFunc[T] {
+	return func(lhs, rhs T) int {
+		return k.compare(k.key(lhs), k.key(rhs))
+	}
+}
+
+// equalRange returns the range [lo, hi) of the order elements with the key
+func (k keyed[K, T, C]) equalRange(o *OrderImpl[T, CompareFunc[T]], key K) (lo, hi int) {
+	sign := int(o.kind)
+	lo = sort.Search(o.storage.len(), func(i int) bool {
+		return k.compare(k.key(o.storage.get(uint(i))), key)*sign >= 0
+	})
+	hi = lo + sort.Search(o.storage.len()-lo, func(i int) bool {
+		return k.compare(k.key(o.storage.get(uint(lo+i))), key)*sign > 0
+	})
+	return
+}
+
+// getByKey returns the first element with the key
+func (k keyed[K, T, C]) getByKey(o *OrderImpl[T, CompareFunc[T]], key K) (ret T, ok bool) {
+	lo, hi := k.equalRange(o, key)
+	if lo == hi {
+		return
+	}
+	return o.storage.get(uint(lo)), true
+}
+
+// upsert replaces the first element with the value key or adds the value
+func (k keyed[K, T, C]) upsert(o *OrderImpl[T, CompareFunc[T]], value T) bool {
+	lo, hi := k.equalRange(o, k.key(value))
+	if lo == hi {
+		o.storage.insert(uint(lo), value)
+		return true
+	}
+	o.storage.set(uint(lo), value)
+	return false
+}
+
+// SetByImpl is a set of elements what are identified by their keys. The key of every
+// element is extracted by the key function and the elements are ordered by keys.
+type SetByImpl[K any, T any, C CompareFunc[K]] struct {
+	Set *SetImpl[T, CompareFunc[T]]
+	keyed[K, T, C]
+}
+
+// MakeSetBy returns a new SetByImpl with a given key and keys compare functions.
+//
+// key: the function used to extract the element key.
+// compareFunc: the function used to compare keys.
+// options: select the set storage (see WithBTree).
+// Returns a new SetByImpl.
+func MakeSetBy[K any, T any, C CompareFunc[K]](key KeyFunc[T, K], compareFunc C, options ...OrderOption) SetByImpl[K, T, C] {
+	k := keyed[K, T, C]{key: key, compare: compareFunc}
+	return SetByImpl[K, T, C]{
+		Set:   NewSet[T](k.elementCompare(), options...),
+		keyed: k,
+	}
+}
+
+// NewSetBy returns a new SetByImpl instance.
+//
+// key: the function used to extract the element key.
+// compareFunc: the function used to compare keys.
+// options: select the set storage (see WithBTree).
+// Returns a pointer to the new SetByImpl.
+func NewSetBy[K any, T any, C CompareFunc[K]](key KeyFunc[T, K], compareFunc C, options ...OrderOption) *SetByImpl[K, T, C] {
+	ret := MakeSetBy(key, compareFunc, options...)
+	return &ret
+}
+
+// WithLocker sets the locker to be used by set and returns the updated set.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the set.
+// returns: a pointer to the updated set.
+func (s *SetByImpl[K, T, C]) WithLocker(locker sync.Locker) *SetByImpl[K, T, C] {
+	s.Set.WithLocker(locker)
+	return s
+}
+
+// Len returns the count of set elements.
+func (s *SetByImpl[K, T, C]) Len() int {
+	return s.Set.Order.Len()
+}
+
+// Empty checks if the set is empty.
+func (s *SetByImpl[K, T, C]) Empty() bool {
+	return s.Set.Empty()
+}
+
+// GetByKey returns the element with the key.
+//
+// Returns the element and true, or zero value and false if there is no such key.
+func (s *SetByImpl[K, T, C]) GetByKey(key K) (T, bool) {
+	s.Set.Order.Locker().Lock()
+	defer s.Set.Order.Locker().Unlock()
+
+	return s.getByKey(s.Set.Order, key)
+}
+
+// HasKey checks if the set contains an element with the key.
+func (s *SetByImpl[K, T, C]) HasKey(key K) bool {
+	s.Set.Order.Locker().Lock()
+	defer s.Set.Order.Locker().Unlock()
+
+	lo, hi := s.equalRange(s.Set.Order, key)
+	return lo != hi
+}
+
+// RemoveKey removes the element with the key.
+//
+// Returns true if the element was removed.
+func (s *SetByImpl[K, T, C]) RemoveKey(key K) bool {
+	s.Set.Order.Locker().Lock()
+	defer s.Set.Order.Locker().Unlock()
+	defer s.Set.Order.check()
+
+	lo, hi := s.equalRange(s.Set.Order, key)
+	if lo == hi {
+		return false
+	}
+	s.Set.Order.storage.remove(uint(lo))
+	return true
+}
+
+// Upsert replaces the element with the same key by value or adds value if there is no such key.
+//
+// Returns true if the value was added and false if the existing element was replaced.
+func (s *SetByImpl[K, T, C]) Upsert(value T) bool {
+	s.Set.Order.Locker().Lock()
+	defer s.Set.Order.Locker().Unlock()
+	defer s.Set.Order.check()
+
+	return s.upsert(s.Set.Order, value)
+}
+
+// OrderByImpl is an order of elements what are ordered by their keys. The key of every
+// element is extracted by the key function, the elements with equal keys are allowed.
+type OrderByImpl[K any, T any, C CompareFunc[K]] struct {
+	Order *OrderImpl[T, CompareFunc[T]]
+	keyed[K, T, C]
+}
+
+// MakeOrderBy returns a new OrderByImpl with a given key and keys compare functions.
+//
+// key: the function used to extract the element key.
+// compareFunc: the function used to compare keys.
+// kind: the order kind to use.
+// options: select the order storage (see WithBTree).
+// Returns a new OrderByImpl.
+func MakeOrderBy[K any, T any, C CompareFunc[K]](key KeyFunc[T, K], compareFunc C, kind OrderKind, options ...OrderOption) OrderByImpl[K, T, C] {
+	k := keyed[K, T, C]{key: key, compare: compareFunc}
+	return OrderByImpl[K, T, C]{
+		Order: NewOrder[T](k.elementCompare(), kind, options...),
+		keyed: k,
+	}
+}
+
+// NewOrderBy returns a new OrderByImpl instance.
+//
+// key: the function used to extract the element key.
+// compareFunc: the function used to compare keys.
+// kind: the order kind to use.
+// options: select the order storage (see WithBTree).
+// Returns a pointer to the new OrderByImpl.
+func NewOrderBy[K any, T any, C CompareFunc[K]](key KeyFunc[T, K], compareFunc C, kind OrderKind, options ...OrderOption) *OrderByImpl[K, T, C] {
+	ret := MakeOrderBy(key, compareFunc, kind, options...)
+	return &ret
+}
+
+// WithLocker sets the locker to be used by order and returns the updated order.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the order.
+// returns: a pointer to the updated order.
+func (o *OrderByImpl[K, T, C]) WithLocker(locker sync.Locker) *OrderByImpl[K, T, C] {
+	o.Order.WithLocker(locker)
+	return o
+}
+
+// Len returns the count of order elements.
+func (o *OrderByImpl[K, T, C]) Len() int {
+	return o.Order.Len()
+}
+
+// Empty checks if the order is empty.
+func (o *OrderByImpl[K, T, C]) Empty() bool {
+	return o.Order.Empty()
+}
+
+// GetByKey returns the first element with the key.
+//
+// Returns the element and true, or zero value and false if there is no such key.
+func (o *OrderByImpl[K, T, C]) GetByKey(key K) (T, bool) {
+	o.Order.Locker().Lock()
+	defer o.Order.Locker().Unlock()
+
+	return o.getByKey(o.Order, key)
+}
+
+// HasKey checks if the order contains an element with the key.
+func (o *OrderByImpl[K, T, C]) HasKey(key K) bool {
+	o.Order.Locker().Lock()
+	defer o.Order.Locker().Unlock()
+
+	lo, hi := o.equalRange(o.Order, key)
+	return lo != hi
+}
+
+// RemoveKey removes all elements with the key.
+//
+// Returns the count of removed elements.
+func (o *OrderByImpl[K, T, C]) RemoveKey(key K) int {
+	o.Order.Locker().Lock()
+	defer o.Order.Locker().Unlock()
+	defer o.Order.check()
+
+	lo, hi := o.equalRange(o.Order, key)
+	o.Order.storage.removeRange(lo, hi)
+	return hi - lo
+}
+
+// Upsert replaces the first element with the same key by value or adds value if there is no such key.
+//
+// Returns true if the value was added and false if the existing element was replaced.
+func (o *OrderByImpl[K, T, C]) Upsert(value T) bool {
+	o.Order.Locker().Lock()
+	defer o.Order.Locker().Unlock()
+	defer o.Order.check()
+
+	return o.upsert(o.Order, value)
+}
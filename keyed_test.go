@@ -0,0 +1,90 @@
+package vector
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type keyedRecord struct {
+	ID   int
+	Name string
+}
+
+func keyedRecordID(r keyedRecord) int {
+	return r.ID
+}
+
+func TestSetBy_Keys(t *testing.T) {
+	s := NewSetBy(keyedRecordID, CompareFunc[int](CompareNumber[int])).WithLocker(&sync.Mutex{})
+	assert.True(t, s.Empty())
+
+	assert.Equal(t, 2, s.Set.Add(keyedRecord{2, "b"}, keyedRecord{1, "a"}, keyedRecord{2, "dup"}))
+	assert.Equal(t, 2, s.Len())
+
+	value, ok := s.GetByKey(2)
+	assert.True(t, ok)
+	assert.Equal(t, keyedRecord{2, "b"}, value)
+
+	_, ok = s.GetByKey(3)
+	assert.False(t, ok)
+
+	assert.True(t, s.HasKey(1))
+	assert.False(t, s.HasKey(3))
+
+	assert.True(t, s.RemoveKey(1))
+	assert.False(t, s.RemoveKey(1))
+	assert.Equal(t, []keyedRecord{{2, "b"}}, s.Set.Data())
+}
+
+func TestSetBy_Upsert(t *testing.T) {
+	s := NewSetBy(keyedRecordID, CompareFunc[int](CompareNumber[int]), WithBTree(2))
+
+	assert.True(t, s.Upsert(keyedRecord{3, "c"}))
+	assert.True(t, s.Upsert(keyedRecord{1, "a"}))
+	assert.False(t, s.Upsert(keyedRecord{3, "C"}))
+	assert.True(t, s.Upsert(keyedRecord{2, "b"}))
+
+	assert.Equal(t, []keyedRecord{{1, "a"}, {2, "b"}, {3, "C"}}, s.Set.Data())
+	assert.True(t, s.Set.Has(keyedRecord{ID: 3}))
+}
+
+func TestOrderBy_Keys(t *testing.T) {
+	o := NewOrderBy(keyedRecordID, CompareFunc[int](CompareNumber[int]), OrderKindDecreasing)
+	o.Order.Add(keyedRecord{1, "a"}, keyedRecord{3, "c"}, keyedRecord{2, "b"}, keyedRecord{2, "bb"})
+	assert.Equal(t, 4, o.Len())
+	assert.Equal(t, []keyedRecord{{3, "c"}, {2, "b"}, {2, "bb"}, {1, "a"}}, o.Order.Data())
+
+	value, ok := o.GetByKey(2)
+	assert.True(t, ok)
+	assert.Equal(t, keyedRecord{2, "b"}, value)
+	assert.True(t, o.HasKey(1))
+	assert.False(t, o.HasKey(4))
+
+	assert.False(t, o.Upsert(keyedRecord{2, "B"}))
+	assert.True(t, o.Upsert(keyedRecord{4, "d"}))
+	assert.Equal(t, []keyedRecord{{4, "d"}, {3, "c"}, {2, "B"}, {2, "bb"}, {1, "a"}}, o.Order.Data())
+
+	assert.Equal(t, 2, o.RemoveKey(2))
+	assert.Equal(t, 0, o.RemoveKey(2))
+	assert.Equal(t, []keyedRecord{{4, "d"}, {3, "c"}, {1, "a"}}, o.Order.Data())
+	assert.False(t, o.Empty())
+}
+
+func TestKeyed_Validation(t *testing.T) {
+	s := NewSetBy(keyedRecordID, CompareFunc[int](CompareNumber[int]), WithValidation())
+	s.Set.Add(keyedRecord{1, "a"}, keyedRecord{2, "b"}, keyedRecord{3, "c"})
+	s.Set.Order.storage.set(0, keyedRecord{5, "e"})
+	assert.PanicsWithError(t, "element at 1: order violation", func() { s.Upsert(keyedRecord{4, "d"}) })
+	assert.PanicsWithError(t, "element at 1: order violation", func() { s.RemoveKey(4) })
+
+	o := NewOrderBy(keyedRecordID, CompareFunc[int](CompareNumber[int]), OrderKindIncreasing, WithValidation())
+	o.Order.Add(keyedRecord{1, "a"}, keyedRecord{2, "b"}, keyedRecord{2, "bb"}, keyedRecord{3, "c"})
+	o.Order.storage.set(0, keyedRecord{5, "e"})
+	assert.PanicsWithError(t, "element at 1: order violation", func() { o.Upsert(keyedRecord{4, "d"}) })
+	o.Order.Repair()
+	assert.Equal(t, 2, o.RemoveKey(2))
+	o.Order.storage.set(0, keyedRecord{5, "e"})
+	assert.PanicsWithError(t, "element at 1: order violation", func() { o.RemoveKey(3) })
+}
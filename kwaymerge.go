@@ -0,0 +1,256 @@
+package vector
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// mergeCursor is a read position in one of the merged storages, value is the element at the position
+type mergeCursor[T any] struct {
+	storage orderedStorage[T]
+	index   int
+	value   T
+	source  int
+}
+
+// mergeHeap is a heap of cursors ordered by their current elements. The cursors with
+// equal elements are ordered by the source index, so the merge is stable.
+type mergeHeap[T any] struct {
+	cursors []*mergeCursor[T]
+	before  func(lhs, rhs T) bool
+}
+
+// Len implements heap.Interface.
+func (h *mergeHeap[T]) Len() int {
+	return len(h.cursors)
+}
+
+// Less implements heap.Interface.
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	lhs := h.cursors[i]
+	rhs := h.cursors[j]
+	switch {
+	case h.before(lhs.value, rhs.value):
+		return true
+	case h.before(rhs.value, lhs.value):
+		return false
+	default:
+		return lhs.source < rhs.source
+	}
+}
+
+// Swap implements heap.Interface.
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i]
+}
+
+// Push implements heap.Interface.
+func (h *mergeHeap[T]) Push(x any) {
+	h.cursors = append(h.cursors, x.(*mergeCursor[T]))
+}
+
+// Pop implements heap.Interface.
+func (h *mergeHeap[T]) Pop() any {
+	last := len(h.cursors) - 1
+	ret := h.cursors[last]
+	h.cursors[last] = nil
+	h.cursors = h.cursors[:last]
+	return ret
+}
+
+// kWayMerger lazily merges many sorted storages into one sorted sequence. The storages are
+// read by position, so they are not copied.
+type kWayMerger[T any] struct {
+	heap *mergeHeap[T]
+}
+
+// newKWayMerger creates a merger of the sorted storages, before defines the storages order
+func newKWayMerger[T any](before func(lhs, rhs T) bool, storages ...orderedStorage[T]) *kWayMerger[T] {
+	h := &mergeHeap[T]{
+		cursors: make([]*mergeCursor[T], 0, len(storages)),
+		before:  before,
+	}
+	for source, storage := range storages {
+		if storage.len() > 0 {
+			h.cursors = append(h.cursors, &mergeCursor[T]{storage: storage, value: storage.get(0), source: source})
+		}
+	}
+	heap.Init(h)
+
+	return &kWayMerger[T]{heap: h}
+}
+
+// peek returns the next element without taking it
+func (m *kWayMerger[T]) peek() (ret T, ok bool) {
+	if m.heap.Len() == 0 {
+		return
+	}
+	return m.heap.cursors[0].value, true
+}
+
+// next takes the next element of the merged sequence
+func (m *kWayMerger[T]) next() (ret T, ok bool) {
+	if m.heap.Len() == 0 {
+		return
+	}
+
+	cursor := m.heap.cursors[0]
+	ret = cursor.value
+	cursor.index++
+	if cursor.index < cursor.storage.len() {
+		cursor.value = cursor.storage.get(uint(cursor.index))
+		heap.Fix(m.heap, 0)
+	} else {
+		heap.Pop(m.heap)
+	}
+
+	return ret, true
+}
+
+// LockOrders locks every distinct order locker once and returns the unlock function. The lockers
+// are locked in the order of their ids, so the concurrent calls with the same orders passed in
+// different order do not deadlock. Use it to hold the orders during MergeIterator and
+// CombineIterator iterations.
+func LockOrders[T any, C CompareFunc[T]](orders ...*OrderImpl[T, C]) (unlock func()) {
+	lockers := make([]*orderLocker, 0, len(orders))
+	for _, order := range orders {
+		lockers = append(lockers, order.locker)
+	}
+	sort.Slice(lockers, func(i, j int) bool {
+		return lockers[i].id < lockers[j].id
+	})
+
+	locked := make([]*orderLocker, 0, len(lockers))
+	for index, locker := range lockers {
+		if index > 0 && locker == lockers[index-1] {
+			continue
+		}
+		locker.Lock()
+		locked = append(locked, locker)
+	}
+
+	return func() {
+		for index := len(locked) - 1; index >= 0; index-- {
+			locked[index].Unlock()
+		}
+	}
+}
+
+// OrderMergeIterator lazily yields the merged elements of many orders. It reads the orders
+// storages by position and does not copy them.
+type OrderMergeIterator[T any] struct {
+	merger  *kWayMerger[T]
+	compare func(lhs, rhs T) int
+	unique  bool
+	replace bool
+}
+
+// newOrderMergeIterator creates the iterator over orders. Only one of equal elements is yielded
+// if unique is set or the first order duplicate policy does not allow duplicates: the last one
+// for the replace policy and the first one otherwise.
+func newOrderMergeIterator[T any, C CompareFunc[T]](unique bool, orders ...*OrderImpl[T, C]) *OrderMergeIterator[T] {
+	if len(orders) == 0 {
+		return &OrderMergeIterator[T]{merger: newKWayMerger[T](nil)}
+	}
+
+	first := orders[0]
+	storages := make([]orderedStorage[T], 0, len(orders))
+	for _, order := range orders {
+		storages = append(storages, order.oriented(first.kind).storage)
+	}
+
+	return &OrderMergeIterator[T]{
+		merger:  newKWayMerger(first.before, storages...),
+		compare: first.compare,
+		unique:  unique || first.duplicates != OrderDuplicatesAllow,
+		replace: first.duplicates == OrderDuplicatesReplace,
+	}
+}
+
+// next returns the next merged element
+func (i *OrderMergeIterator[T]) next() (value T, ok bool) {
+	value, ok = i.merger.next()
+	if !ok || !i.unique {
+		return
+	}
+
+	for {
+		equal, more := i.merger.peek()
+		if !more || i.compare(equal, value) != 0 {
+			return
+		}
+		i.merger.next()
+		if i.replace {
+			value = equal
+		}
+	}
+}
+
+// Next returns the next merged element and true, or zero value and false if there are no more elements.
+func (i *OrderMergeIterator[T]) Next() (T, bool) {
+	return i.next()
+}
+
+// Range calls the callback for every remaining merged element. If the callback returns an error,
+// the iteration stops and returns the error.
+func (i *OrderMergeIterator[T]) Range(callback func(index int, value T) error) error {
+	index := 0
+	for value, ok := i.next(); ok; value, ok = i.next() {
+		if err := callback(index, value); err != nil {
+			return err
+		}
+		index++
+	}
+	return nil
+}
+
+// MergeIterator returns the iterator what lazily merges orders in the kind of the first order.
+// The orders of the opposite kind are walked in the reverse direction. The equal elements are
+// yielded in accordance to the first order duplicate policy as Merge does.
+//
+// The iterator does not lock the orders and walks them in place: the caller must hold the orders
+// locked by LockOrders from the iterator creation to the end of the iteration, or guarantee
+// otherwise that the orders are not modified meanwhile. Use MergeAll to get a merged copy.
+//
+//	unlock := LockOrders(o0, o1)
+//	defer unlock()
+//	iterator := MergeIterator(o0, o1)
+func MergeIterator[T any, C CompareFunc[T]](orders ...*OrderImpl[T, C]) *OrderMergeIterator[T] {
+	return newOrderMergeIterator(false, orders...)
+}
+
+// CombineIterator returns the iterator what lazily merges orders as MergeIterator does, but
+// yields the equal elements once as Combine does. The orders must not be modified during the
+// iteration as for MergeIterator.
+func CombineIterator[T any, C CompareFunc[T]](orders ...*OrderImpl[T, C]) *OrderMergeIterator[T] {
+	return newOrderMergeIterator(true, orders...)
+}
+
+// collectOrders merges orders into a new order of the first order kind and storage type
+func collectOrders[T any, C CompareFunc[T]](unique bool, orders ...*OrderImpl[T, C]) *OrderImpl[T, C] {
+	if len(orders) == 0 {
+		return nil
+	}
+	defer LockOrders(orders...)()
+
+	ret := orders[0].spawn()
+	iterator := newOrderMergeIterator(unique, orders...)
+	for value, ok := iterator.next(); ok; value, ok = iterator.next() {
+		ret.storage.append(value)
+	}
+	return ret
+}
+
+// MergeAll merges many orders in one heap driven pass. The result has the kind, the storage
+// type and the duplicate policy of the first order, the orders of the opposite kind are walked
+// in the reverse direction. The equal elements are kept in accordance to the duplicate policy
+// as Merge does. Returns nil if there are no orders.
+func MergeAll[T any, C CompareFunc[T]](orders ...*OrderImpl[T, C]) *OrderImpl[T, C] {
+	return collectOrders(false, orders...)
+}
+
+// CombineAll merges many orders as MergeAll does and omits non unique elements in the resulting order.
+// Returns nil if there are no orders.
+func CombineAll[T any, C CompareFunc[T]](orders ...*OrderImpl[T, C]) *OrderImpl[T, C] {
+	return collectOrders(true, orders...)
+}
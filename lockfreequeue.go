@@ -0,0 +1,157 @@
+package vector
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// ErrFullQueue is raised by Enqueue when the bounded queue is full
+	ErrFullQueue = errors.New("full queue")
+)
+
+// cacheLinePad prevents false sharing between hot fields
+type cacheLinePad [64]byte
+
+// lockFreeSlot is a sequence-numbered cell of the lock-free queue ring
+type lockFreeSlot[T any] struct {
+	sequence atomic.Uint64
+	value    T
+}
+
+// LockFreeQueueImpl is a lock-free bounded multi-producer/multi-consumer FIFO queue
+// based on the ring of sequence-numbered slots (D. Vyukov's algorithm).
+type LockFreeQueueImpl[T any] struct {
+	_          cacheLinePad
+	enqueuePos atomic.Uint64
+	_          cacheLinePad
+	dequeuePos atomic.Uint64
+	_          cacheLinePad
+	mask       uint64
+	slots      []lockFreeSlot[T]
+}
+
+// NewLockFreeQueue creates a new LockFreeQueueImpl what can hold at least capacity elements.
+// The capacity is rounded up to the nearest power of two. There is no MakeLockFreeQueue
+// because the queue contains atomics and must not be copied.
+//
+// capacity: the minimal capacity of the queue.
+// Returns a pointer to the newly created LockFreeQueueImpl.
+func NewLockFreeQueue[T any](capacity int) *LockFreeQueueImpl[T] {
+	size := uint64(2)
+	for size < uint64(capacity) {
+		size <<= 1
+	}
+
+	ret := &LockFreeQueueImpl[T]{
+		mask:  size - 1,
+		slots: make([]lockFreeSlot[T], size),
+	}
+	for i := range ret.slots {
+		ret.slots[i].sequence.Store(uint64(i))
+	}
+
+	return ret
+}
+
+// Cap returns the capacity of the queue.
+func (q *LockFreeQueueImpl[T]) Cap() int {
+	return len(q.slots)
+}
+
+// Len returns the number of elements in the queue. The result is a snapshot
+// and may be stale under concurrent access.
+func (q *LockFreeQueueImpl[T]) Len() int {
+	for {
+		dequeuePos := q.dequeuePos.Load()
+		enqueuePos := q.enqueuePos.Load()
+		if dequeuePos == q.dequeuePos.Load() {
+			if enqueuePos < dequeuePos {
+				return 0
+			}
+			return int(enqueuePos - dequeuePos)
+		}
+	}
+}
+
+// Empty checks if the queue is empty.
+func (q *LockFreeQueueImpl[T]) Empty() bool {
+	return q.Len() == 0
+}
+
+// TryEnqueue adds an element to the back of the queue.
+//
+// value: the element to be added to the queue.
+// Returns false if the queue is full.
+func (q *LockFreeQueueImpl[T]) TryEnqueue(value T) bool {
+	pos := q.enqueuePos.Load()
+	for {
+		slot := &q.slots[pos&q.mask]
+		sequence := slot.sequence.Load()
+		diff := int64(sequence) - int64(pos)
+
+		switch {
+		case diff == 0:
+			if q.enqueuePos.CompareAndSwap(pos, pos+1) {
+				slot.value = value
+				slot.sequence.Store(pos + 1)
+				return true
+			}
+			pos = q.enqueuePos.Load()
+		case diff < 0:
+			return false
+		default:
+			pos = q.enqueuePos.Load()
+		}
+	}
+}
+
+// Enqueue adds an element to the back of the queue.
+// Panics if the queue is full.
+//
+// value: the element to be added to the queue.
+func (q *LockFreeQueueImpl[T]) Enqueue(value T) {
+	if !q.TryEnqueue(value) {
+		panic(ErrFullQueue)
+	}
+}
+
+// TryDequeue removes and returns the first element from the queue.
+//
+// Returns the element and true, or zero value and false if the queue is empty.
+func (q *LockFreeQueueImpl[T]) TryDequeue() (ret T, ok bool) {
+	pos := q.dequeuePos.Load()
+	for {
+		slot := &q.slots[pos&q.mask]
+		sequence := slot.sequence.Load()
+		diff := int64(sequence) - int64(pos+1)
+
+		switch {
+		case diff == 0:
+			if q.dequeuePos.CompareAndSwap(pos, pos+1) {
+				var zero T
+				ret = slot.value
+				slot.value = zero
+				slot.sequence.Store(pos + q.mask + 1)
+				return ret, true
+			}
+			pos = q.dequeuePos.Load()
+		case diff < 0:
+			return
+		default:
+			pos = q.dequeuePos.Load()
+		}
+	}
+}
+
+// Dequeue removes and returns the first element from the queue.
+// Panics if the queue is empty.
+//
+// T, the type of the queue elements.
+func (q *LockFreeQueueImpl[T]) Dequeue() T {
+	ret, ok := q.TryDequeue()
+	if !ok {
+		panic(ErrEmptyQueue)
+	}
+	return ret
+}
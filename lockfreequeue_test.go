@@ -0,0 +1,144 @@
+package vector
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFreeQueue(t *testing.T) {
+	var q Queue[int] = NewLockFreeQueue[int](3)
+
+	assert.True(t, q.Empty())
+	assert.Equal(t, 4, q.(*LockFreeQueueImpl[int]).Cap())
+
+	for i := 0; i < 4; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 4, q.Len())
+	assert.Panics(t, func() {
+		q.Enqueue(5)
+	})
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, i, q.Dequeue())
+	}
+	assert.True(t, q.Empty())
+	assert.Panics(t, func() {
+		q.Dequeue()
+	})
+}
+
+func TestLockFreeQueue_WrapAround(t *testing.T) {
+	q := NewLockFreeQueue[int](4)
+
+	for i := 0; i < 100; i++ {
+		assert.True(t, q.TryEnqueue(i))
+		assert.True(t, q.TryEnqueue(i+1))
+		v, ok := q.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i, v)
+		v, ok = q.TryDequeue()
+		assert.True(t, ok)
+		assert.Equal(t, i+1, v)
+	}
+
+	_, ok := q.TryDequeue()
+	assert.False(t, ok)
+}
+
+func TestLockFreeQueue_Stress(t *testing.T) {
+	const (
+		producers = 8
+		consumers = 8
+		perWorker = 2000
+	)
+
+	q := NewLockFreeQueue[int](64)
+
+	var producersWg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		producersWg.Add(1)
+		go func(p int) {
+			defer producersWg.Done()
+			for i := 0; i < perWorker; i++ {
+				for !q.TryEnqueue(p*perWorker + i) {
+					runtime.Gosched()
+				}
+			}
+		}(p)
+	}
+
+	results := make([][]int, consumers)
+	var consumersWg sync.WaitGroup
+	var received atomic.Int64
+	for c := 0; c < consumers; c++ {
+		consumersWg.Add(1)
+		go func(c int) {
+			defer consumersWg.Done()
+			for {
+				if received.Load() == producers*perWorker {
+					return
+				}
+				v, ok := q.TryDequeue()
+				if !ok {
+					runtime.Gosched()
+					continue
+				}
+				results[c] = append(results[c], v)
+				received.Add(1)
+			}
+		}(c)
+	}
+
+	producersWg.Wait()
+	consumersWg.Wait()
+
+	seen := make([]bool, producers*perWorker)
+	for _, values := range results {
+		last := make(map[int]int)
+		for _, v := range values {
+			assert.False(t, seen[v])
+			seen[v] = true
+
+			// Values of the same producer must be consumed in order.
+			producer := v / perWorker
+			if prev, ok := last[producer]; ok {
+				assert.Less(t, prev, v)
+			}
+			last[producer] = v
+		}
+	}
+	for _, v := range seen {
+		assert.True(t, v)
+	}
+	assert.True(t, q.Empty())
+}
+
+func BenchmarkLockedQueue(b *testing.B) {
+	q := NewQueue[int](QueueKindFifo).WithLocker(&sync.Mutex{})
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			q.Enqueue(1)
+			q.Dequeue()
+		}
+	})
+}
+
+func BenchmarkLockFreeQueue(b *testing.B) {
+	q := NewLockFreeQueue[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			for !q.TryEnqueue(1) {
+			}
+			for {
+				if _, ok := q.TryDequeue(); ok {
+					break
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,262 @@
+package vector
+
+import "sync"
+
+// MinMaxStack is an interface of stack what tracks its extremes
+type MinMaxStack[T any] interface {
+	Stack[T]
+	Min() T
+	Max() T
+}
+
+// MinMaxQueue is an interface of queue what tracks its extremes
+type MinMaxQueue[T any] interface {
+	Queue[T]
+	Min() T
+	Max() T
+}
+
+// minMaxEntry is a stack element with the extremes of the stack below it (inclusive)
+type minMaxEntry[T any] struct {
+	value T
+	min   T
+	max   T
+}
+
+// MinMaxStackImpl is an implementation of stack with O(1) Min and Max
+type MinMaxStackImpl[T any, C CompareFunc[T]] struct {
+	Stack   *StackImpl[minMaxEntry[T]]
+	compare C
+}
+
+// MakeMinMaxStack creates a new MinMaxStackImpl with the given compare function.
+//
+// compareFunc: the compare function used to find extremes.
+// Returns a new MinMaxStackImpl.
+func MakeMinMaxStack[T any, C CompareFunc[T]](compareFunc C) MinMaxStackImpl[T, C] {
+	return MinMaxStackImpl[T, C]{
+		Stack:   NewStack[minMaxEntry[T]](),
+		compare: compareFunc,
+	}
+}
+
+// NewMinMaxStack creates a new MinMaxStackImpl with the given compare function.
+//
+// compareFunc: the compare function used to find extremes.
+// Returns a pointer to the new MinMaxStackImpl.
+func NewMinMaxStack[T any, C CompareFunc[T]](compareFunc C) *MinMaxStackImpl[T, C] {
+	ret := MakeMinMaxStack[T](compareFunc)
+	return &ret
+}
+
+// WithLocker sets the locker for the stack.
+//
+// locker: a sync.Locker interface implementation used to synchronize access to the stack.
+// Returns the modified stack, allowing for method chaining.
+func (s *MinMaxStackImpl[T, C]) WithLocker(locker sync.Locker) *MinMaxStackImpl[T, C] {
+	s.Stack.WithLocker(locker)
+	return s
+}
+
+// len returns the number of elements in the stack
+func (s *MinMaxStackImpl[T, C]) len() int {
+	return s.Stack.Vector.len()
+}
+
+// Len returns the number of elements in the stack
+func (s *MinMaxStackImpl[T, C]) Len() int {
+	return s.Stack.Len()
+}
+
+// Empty returns true if the stack is empty
+func (s *MinMaxStackImpl[T, C]) Empty() bool {
+	return s.Stack.Empty()
+}
+
+// push pushes a value onto the stack
+func (s *MinMaxStackImpl[T, C]) push(value T) {
+	entry := minMaxEntry[T]{value: value, min: value, max: value}
+	if s.len() > 0 {
+		top := s.Stack.Vector.last()
+		if s.compare(top.min, value) < 0 {
+			entry.min = top.min
+		}
+		if s.compare(top.max, value) > 0 {
+			entry.max = top.max
+		}
+	}
+	s.Stack.Vector.append(entry)
+}
+
+// Push pushes a value onto the stack
+func (s *MinMaxStackImpl[T, C]) Push(value T) {
+	s.Stack.Vector.Locker().Lock()
+	defer s.Stack.Vector.Locker().Unlock()
+
+	s.push(value)
+}
+
+// Top returns the top element of stack
+func (s *MinMaxStackImpl[T, C]) Top() T {
+	return s.Stack.Top().value
+}
+
+// pop removes the element at the top of the stack
+func (s *MinMaxStackImpl[T, C]) pop() T {
+	return s.Stack.Vector.removeLast().value
+}
+
+// Pop removes the element at the top of the stack
+func (s *MinMaxStackImpl[T, C]) Pop() T {
+	return s.Stack.Pop().value
+}
+
+// min returns the minimal element of the stack
+func (s *MinMaxStackImpl[T, C]) min() T {
+	return s.Stack.Vector.last().min
+}
+
+// Min returns the minimal element of the stack. Panics if the stack is empty.
+func (s *MinMaxStackImpl[T, C]) Min() T {
+	return s.Stack.Top().min
+}
+
+// max returns the maximal element of the stack
+func (s *MinMaxStackImpl[T, C]) max() T {
+	return s.Stack.Vector.last().max
+}
+
+// Max returns the maximal element of the stack. Panics if the stack is empty.
+func (s *MinMaxStackImpl[T, C]) Max() T {
+	return s.Stack.Top().max
+}
+
+// MinMaxQueueImpl is an implementation of FIFO queue with O(1) Min and Max,
+// built on two MinMaxStackImpl (the back one receives elements, the front one gives them).
+type MinMaxQueueImpl[T any, C CompareFunc[T]] struct {
+	locker  sync.Locker
+	back    *MinMaxStackImpl[T, C]
+	front   *MinMaxStackImpl[T, C]
+	compare C
+}
+
+// MakeMinMaxQueue creates a new MinMaxQueueImpl with the given compare function.
+//
+// compareFunc: the compare function used to find extremes.
+// Returns a new MinMaxQueueImpl.
+func MakeMinMaxQueue[T any, C CompareFunc[T]](compareFunc C) MinMaxQueueImpl[T, C] {
+	return MinMaxQueueImpl[T, C]{
+		locker:  NewLockerStub(),
+		back:    NewMinMaxStack[T](compareFunc),
+		front:   NewMinMaxStack[T](compareFunc),
+		compare: compareFunc,
+	}
+}
+
+// NewMinMaxQueue creates a new MinMaxQueueImpl with the given compare function.
+//
+// compareFunc: the compare function used to find extremes.
+// Returns a pointer to the new MinMaxQueueImpl.
+func NewMinMaxQueue[T any, C CompareFunc[T]](compareFunc C) *MinMaxQueueImpl[T, C] {
+	ret := MakeMinMaxQueue[T](compareFunc)
+	return &ret
+}
+
+// WithLocker sets the locker for the queue.
+//
+// locker: the synchronization locker to be used.
+// Returns a pointer to the modified queue.
+func (q *MinMaxQueueImpl[T, C]) WithLocker(locker sync.Locker) *MinMaxQueueImpl[T, C] {
+	q.locker = locker
+	return q
+}
+
+// len returns the number of elements in the queue
+func (q *MinMaxQueueImpl[T, C]) len() int {
+	return q.back.len() + q.front.len()
+}
+
+// Len returns the number of elements in the queue.
+func (q *MinMaxQueueImpl[T, C]) Len() int {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	return q.len()
+}
+
+// Empty checks if the queue is empty.
+func (q *MinMaxQueueImpl[T, C]) Empty() bool {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	return q.len() == 0
+}
+
+// Enqueue adds an element to the back of the queue.
+//
+// value: the element to be added to the queue.
+func (q *MinMaxQueueImpl[T, C]) Enqueue(value T) {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	q.back.push(value)
+}
+
+// dequeue removes and returns the first element from the queue
+func (q *MinMaxQueueImpl[T, C]) dequeue() T {
+	if q.len() == 0 {
+		panic(ErrEmptyQueue)
+	}
+
+	if q.front.len() == 0 {
+		for q.back.len() > 0 {
+			q.front.push(q.back.pop())
+		}
+	}
+
+	return q.front.pop()
+}
+
+// Dequeue removes and returns the first element from the queue.
+// Panics if the queue is empty.
+func (q *MinMaxQueueImpl[T, C]) Dequeue() T {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	return q.dequeue()
+}
+
+// extreme returns the extreme of the both stacks, sign selects min (-1) or max (1)
+func (q *MinMaxQueueImpl[T, C]) extreme(sign int, get func(*MinMaxStackImpl[T, C]) T) T {
+	switch {
+	case q.len() == 0:
+		panic(ErrEmptyQueue)
+	case q.front.len() == 0:
+		return get(q.back)
+	case q.back.len() == 0:
+		return get(q.front)
+	}
+
+	front := get(q.front)
+	back := get(q.back)
+	if q.compare(back, front)*sign > 0 {
+		return back
+	}
+	return front
+}
+
+// Min returns the minimal element of the queue. Panics if the queue is empty.
+func (q *MinMaxQueueImpl[T, C]) Min() T {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	return q.extreme(-1, (*MinMaxStackImpl[T, C]).min)
+}
+
+// Max returns the maximal element of the queue. Panics if the queue is empty.
+func (q *MinMaxQueueImpl[T, C]) Max() T {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+
+	return q.extreme(1, (*MinMaxStackImpl[T, C]).max)
+}
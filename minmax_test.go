@@ -0,0 +1,70 @@
+package vector
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	var s MinMaxStack[int] = NewMinMaxStack[int, CompareFunc[int]](CompareNumber[int]).WithLocker(&sync.Mutex{})
+
+	assert.True(t, s.Empty())
+	assert.Panics(t, func() {
+		s.Min()
+	})
+
+	s.Push(5)
+	s.Push(3)
+	s.Push(7)
+	s.Push(3)
+	assert.Equal(t, 4, s.Len())
+	assert.Equal(t, 3, s.Min())
+	assert.Equal(t, 7, s.Max())
+
+	assert.Equal(t, 3, s.Pop())
+	assert.Equal(t, 3, s.Min())
+	assert.Equal(t, 7, s.Pop())
+	assert.Equal(t, 5, s.Max())
+	assert.Equal(t, 3, s.Pop())
+	assert.Equal(t, 5, s.Min())
+	assert.Equal(t, 5, s.Top())
+	assert.Equal(t, 5, s.Pop())
+	assert.True(t, s.Empty())
+}
+
+func TestMinMaxQueue(t *testing.T) {
+	var q MinMaxQueue[int] = NewMinMaxQueue[int, CompareFunc[int]](CompareNumber[int]).WithLocker(&sync.Mutex{})
+
+	assert.True(t, q.Empty())
+	assert.Panics(t, func() {
+		q.Max()
+	})
+	assert.Panics(t, func() {
+		q.Dequeue()
+	})
+
+	window := []int{}
+	for _, value := range []int{4, 2, 12, 3, 8, 1, 9, 5} {
+		q.Enqueue(value)
+		window = append(window, value)
+		if len(window) > 3 {
+			assert.Equal(t, window[0], q.Dequeue())
+			window = window[1:]
+		}
+
+		expectedMin, expectedMax := window[0], window[0]
+		for _, v := range window {
+			if v < expectedMin {
+				expectedMin = v
+			}
+			if v > expectedMax {
+				expectedMax = v
+			}
+		}
+		assert.Equal(t, len(window), q.Len())
+		assert.Equal(t, expectedMin, q.Min())
+		assert.Equal(t, expectedMax, q.Max())
+	}
+}
@@ -0,0 +1,264 @@
+package vector
+
+import (
+	"sort"
+	"sync"
+)
+
+// MultisetEntry is a multiset element with its count
+type MultisetEntry[T any] struct {
+	Value T
+	Count int
+}
+
+// MultisetImpl is an implementation of multiset (bag), the ordered set of distinct
+// elements where each element has a count of its occurrences.
+type MultisetImpl[T any, C CompareFunc[T]] struct {
+	Order   *OrderImpl[MultisetEntry[T], CompareFunc[MultisetEntry[T]]]
+	compare C
+}
+
+// MakeMultiset returns a new MultisetImpl with a given compare function.
+//
+// compareFunc: the function used to compare elements.
+// Returns a new MultisetImpl.
+func MakeMultiset[T any, C CompareFunc[T]](compareFunc C) MultisetImpl[T, C] {
+	entryCompare := func(lhs, rhs MultisetEntry[T]) int {
+		return compareFunc(lhs.Value, rhs.Value)
+	}
+	return MultisetImpl[T, C]{
+		Order:   NewOrder[MultisetEntry[T]](CompareFunc[MultisetEntry[T]](entryCompare), OrderKindIncreasing),
+		compare: compareFunc,
+	}
+}
+
+// NewMultiset returns a new MultisetImpl instance.
+//
+// compareFunc: the function used to compare elements.
+// Returns a pointer to the new MultisetImpl.
+func NewMultiset[T any, C CompareFunc[T]](compareFunc C) *MultisetImpl[T, C] {
+	ret := MakeMultiset[T](compareFunc)
+	return &ret
+}
+
+// WithLocker sets the locker to be used by multiset and returns the updated multiset.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the multiset.
+// returns: a pointer to the updated multiset.
+func (m *MultisetImpl[T, C]) WithLocker(locker sync.Locker) *MultisetImpl[T, C] {
+	m.Order.WithLocker(locker)
+	return m
+}
+
+// Empty checks if the multiset is empty.
+func (m *MultisetImpl[T, C]) Empty() bool {
+	return m.Order.Empty()
+}
+
+// Len returns the total count of the multiset elements including repetitions.
+func (m *MultisetImpl[T, C]) Len() (ret int) {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	for _, entry := range m.Order.storage.Data() {
+		ret += entry.Count
+	}
+	return
+}
+
+// indexOf returns the index of the value entry or -1
+func (m *MultisetImpl[T, C]) indexOf(value T) int {
+	return m.Order.firstIndexOf(MultisetEntry[T]{Value: value})
+}
+
+// add adds n occurrences of value
+func (m *MultisetImpl[T, C]) add(value T, n int) int {
+	index := m.indexOf(value)
+	if n <= 0 {
+		if index == -1 {
+			return 0
+		}
+		return m.Order.storage.get(uint(index)).Count
+	}
+
+	if index == -1 {
+		m.Order.add(MultisetEntry[T]{Value: value, Count: n})
+		return n
+	}
+
+	entry := m.Order.storage.get(uint(index))
+	entry.Count += n
+	m.Order.storage.set(uint(index), entry)
+	return entry.Count
+}
+
+// Add adds n occurrences of value to the multiset.
+//
+// Returns the count of value after adding.
+func (m *MultisetImpl[T, C]) Add(value T, n int) int {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.add(value, n)
+}
+
+// Remove removes up to n occurrences of value from the multiset.
+//
+// Returns the count of actually removed occurrences.
+func (m *MultisetImpl[T, C]) Remove(value T, n int) int {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	index := m.indexOf(value)
+	if index == -1 || n <= 0 {
+		return 0
+	}
+
+	entry := m.Order.storage.get(uint(index))
+	if entry.Count > n {
+		entry.Count -= n
+		m.Order.storage.set(uint(index), entry)
+		return n
+	}
+
+	removed := entry.Count
+	m.Order.storage.remove(uint(index))
+	return removed
+}
+
+// Count returns the count of value occurrences in the multiset.
+func (m *MultisetImpl[T, C]) Count(value T) int {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	index := m.indexOf(value)
+	if index == -1 {
+		return 0
+	}
+	return m.Order.storage.get(uint(index)).Count
+}
+
+// Has checks if the multiset contains the value.
+func (m *MultisetImpl[T, C]) Has(value T) bool {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.indexOf(value) != -1
+}
+
+// Distinct returns the sorted distinct elements of the multiset.
+func (m *MultisetImpl[T, C]) Distinct() []T {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	ret := make([]T, 0, m.Order.storage.len())
+	for _, entry := range m.Order.storage.Data() {
+		ret = append(ret, entry.Value)
+	}
+	return ret
+}
+
+// Entries returns a copy of the sorted multiset entries.
+func (m *MultisetImpl[T, C]) Entries() []MultisetEntry[T] {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return append([]MultisetEntry[T]{}, m.Order.storage.Data()...)
+}
+
+// Range enumerates the multiset entries in the elements order.
+func (m *MultisetImpl[T, C]) Range(callback func(index int, entry MultisetEntry[T]) error) error {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.Order.storage.xrange(callback)
+}
+
+// MostCommon returns up to k entries with the largest counts. Entries with
+// equal counts keep the elements order.
+func (m *MultisetImpl[T, C]) MostCommon(k int) []MultisetEntry[T] {
+	ret := m.Entries()
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Count > ret[j].Count
+	})
+
+	if k < 0 {
+		k = 0
+	}
+	if k < len(ret) {
+		ret = ret[:k]
+	}
+	return ret
+}
+
+// combine merges multisets entries in one pass, the count of the resulting entry is
+// calculated by the counts function from both counts (zero for absent entry).
+func (m *MultisetImpl[T, C]) combine(rhs *MultisetImpl[T, C], counts func(lhs, rhs int) int) *MultisetImpl[T, C] {
+	defer m.Order.lockBoth(rhs.Order)()
+
+	ret := NewMultiset[T](m.compare)
+	lhsData := m.Order.storage.Data()
+	rhsData := rhs.Order.storage.Data()
+	lhsIndex := 0
+	rhsIndex := 0
+
+	appendEntry := func(value T, count int) {
+		if count > 0 {
+			ret.Order.storage.append(MultisetEntry[T]{Value: value, Count: count})
+		}
+	}
+
+	for lhsIndex < len(lhsData) || rhsIndex < len(rhsData) {
+		switch {
+		case rhsIndex >= len(rhsData) ||
+			(lhsIndex < len(lhsData) && m.compare(lhsData[lhsIndex].Value, rhsData[rhsIndex].Value) < 0):
+			appendEntry(lhsData[lhsIndex].Value, counts(lhsData[lhsIndex].Count, 0))
+			lhsIndex++
+		case lhsIndex >= len(lhsData) ||
+			m.compare(lhsData[lhsIndex].Value, rhsData[rhsIndex].Value) > 0:
+			appendEntry(rhsData[rhsIndex].Value, counts(0, rhsData[rhsIndex].Count))
+			rhsIndex++
+		default:
+			appendEntry(lhsData[lhsIndex].Value, counts(lhsData[lhsIndex].Count, rhsData[rhsIndex].Count))
+			lhsIndex++
+			rhsIndex++
+		}
+	}
+
+	return ret
+}
+
+// Union constructs a new multiset where each element count is the maximum of its counts in both multisets.
+func (m *MultisetImpl[T, C]) Union(rhs *MultisetImpl[T, C]) *MultisetImpl[T, C] {
+	return m.combine(rhs, func(lhs, rhs int) int {
+		if lhs > rhs {
+			return lhs
+		}
+		return rhs
+	})
+}
+
+// Intersection constructs a new multiset where each element count is the minimum of its counts in both multisets.
+func (m *MultisetImpl[T, C]) Intersection(rhs *MultisetImpl[T, C]) *MultisetImpl[T, C] {
+	return m.combine(rhs, func(lhs, rhs int) int {
+		if lhs < rhs {
+			return lhs
+		}
+		return rhs
+	})
+}
+
+// Sum constructs a new multiset where each element count is the sum of its counts in both multisets.
+func (m *MultisetImpl[T, C]) Sum(rhs *MultisetImpl[T, C]) *MultisetImpl[T, C] {
+	return m.combine(rhs, func(lhs, rhs int) int {
+		return lhs + rhs
+	})
+}
+
+// Difference constructs a new multiset where each element count is its count in the multiset
+// decreased by its count in rhs.
+func (m *MultisetImpl[T, C]) Difference(rhs *MultisetImpl[T, C]) *MultisetImpl[T, C] {
+	return m.combine(rhs, func(lhs, rhs int) int {
+		return lhs - rhs
+	})
+}
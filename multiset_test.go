@@ -0,0 +1,78 @@
+package vector
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiset_AddRemove(t *testing.T) {
+	m := NewMultiset[string, CompareFunc[string]](CompareString[string]).WithLocker(&sync.Mutex{})
+	assert.True(t, m.Empty())
+
+	assert.Equal(t, 2, m.Add("b", 2))
+	assert.Equal(t, 1, m.Add("a", 1))
+	assert.Equal(t, 5, m.Add("b", 3))
+	assert.Equal(t, 1, m.Add("a", 0))
+	assert.Equal(t, 0, m.Add("c", -1))
+
+	assert.Equal(t, 5, m.Count("b"))
+	assert.Equal(t, 0, m.Count("c"))
+	assert.Equal(t, 6, m.Len())
+	assert.Equal(t, []string{"a", "b"}, m.Distinct())
+
+	assert.Equal(t, 2, m.Remove("b", 2))
+	assert.Equal(t, 3, m.Count("b"))
+	assert.Equal(t, 1, m.Remove("a", 10))
+	assert.False(t, m.Has("a"))
+	assert.Equal(t, 0, m.Remove("c", 1))
+	assert.Equal(t, []MultisetEntry[string]{{Value: "b", Count: 3}}, m.Entries())
+}
+
+func TestMultiset_MostCommon(t *testing.T) {
+	m := NewMultiset[string, CompareFunc[string]](CompareString[string])
+	m.Add("x", 1)
+	m.Add("c", 3)
+	m.Add("b", 5)
+	m.Add("a", 3)
+
+	assert.Equal(t, []MultisetEntry[string]{
+		{Value: "b", Count: 5},
+		{Value: "a", Count: 3},
+	}, m.MostCommon(2))
+	assert.Len(t, m.MostCommon(10), 4)
+	assert.Empty(t, m.MostCommon(0))
+}
+
+func TestMultiset_Operations(t *testing.T) {
+	m0 := NewMultiset[int, CompareFunc[int]](CompareNumber[int])
+	m1 := NewMultiset[int, CompareFunc[int]](CompareNumber[int])
+
+	m0.Add(1, 2)
+	m0.Add(2, 3)
+	m0.Add(3, 1)
+	m1.Add(2, 1)
+	m1.Add(3, 4)
+	m1.Add(4, 2)
+
+	assert.Equal(t, []MultisetEntry[int]{
+		{Value: 1, Count: 2}, {Value: 2, Count: 3}, {Value: 3, Count: 4}, {Value: 4, Count: 2},
+	}, m0.Union(m1).Entries())
+	assert.Equal(t, []MultisetEntry[int]{
+		{Value: 2, Count: 1}, {Value: 3, Count: 1},
+	}, m0.Intersection(m1).Entries())
+	assert.Equal(t, []MultisetEntry[int]{
+		{Value: 1, Count: 2}, {Value: 2, Count: 4}, {Value: 3, Count: 5}, {Value: 4, Count: 2},
+	}, m0.Sum(m1).Entries())
+	assert.Equal(t, []MultisetEntry[int]{
+		{Value: 1, Count: 2}, {Value: 2, Count: 2},
+	}, m0.Difference(m1).Entries())
+
+	var counts []int
+	assert.NoError(t, m0.Range(func(index int, entry MultisetEntry[int]) error {
+		counts = append(counts, entry.Count)
+		return nil
+	}))
+	assert.Equal(t, []int{2, 3, 1}, counts)
+}
@@ -1,8 +1,27 @@
 package vector
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
+)
+
+// bulkAddThreshold is the count of added elements above which Add to the vector storage sorts
+// the elements and merges them with the stored ones in one pass instead of inserting them one by one
+const bulkAddThreshold = 16
+
+var (
+	// ErrInvalidQuantile raised by Quantile when the quantile is out of [0, 1] range
+	ErrInvalidQuantile = errors.New("invalid quantile")
+
+	// ErrOrderViolation returned by Validate when an element is placed before its predecessor
+	ErrOrderViolation = errors.New("order violation")
+
+	// ErrDuplicateElement returned by Validate when a set element is equal to its predecessor
+	ErrDuplicateElement = errors.New("duplicate element")
 )
 
 // OrderKind is either increasing or decreasing
@@ -16,6 +35,27 @@ const (
 	OrderKindDecreasing OrderKind = -1
 )
 
+// OrderDuplicatePolicy defines how the order adds an element equal to the stored one
+type OrderDuplicatePolicy int
+
+const (
+	// OrderDuplicatesAllow stores the equal elements, a new element is placed after the
+	// stored equal ones, so the equal elements keep the order they were added in.
+	OrderDuplicatesAllow OrderDuplicatePolicy = iota
+
+	// OrderDuplicatesReject refuses an element equal to the stored one. The refused elements
+	// are not counted by Add.
+	OrderDuplicatesReject
+
+	// OrderDuplicatesReplace replaces the stored element by the equal new one.
+	OrderDuplicatesReplace
+
+	// OrderDuplicatesKeepFirst keeps the stored element and drops the equal new one as
+	// OrderDuplicatesReject does, but the dropped elements are counted by Add as accepted,
+	// so adding an element is idempotent.
+	OrderDuplicatesKeepFirst
+)
+
 // Order is an interface of order
 type Order[T any, C CompareFunc[T]] interface {
 	Empty() bool
@@ -23,13 +63,38 @@ type Order[T any, C CompareFunc[T]] interface {
 	Data() []T
 	Add(value T) uint
 	FirstIndexOf(value T) int
+	LastIndexOf(value T) int
+	Count(value T) int
+	EqualRange(value T) (lo, hi int)
+	Remove(value T) bool
+	RemoveAll(value T) int
+	RemoveAt(index int) T
+}
+
+// orderLockerIDs is the last assigned orderLocker id
+var orderLockerIDs atomic.Uint64
+
+// orderLocker holds the order locker. It is shared by pointer between the order and its
+// views, so the locker set by WithLocker is used by all of them. The id is a stable key
+// what defines the order of locking many orders at once.
+type orderLocker struct {
+	sync.Locker
+	id uint64
+}
+
+// newOrderLocker creates a holder of the locker stub
+func newOrderLocker() *orderLocker {
+	return &orderLocker{Locker: NewLockerStub(), id: orderLockerIDs.Add(1)}
 }
 
 // OrderImpl is an implementation of order
 type OrderImpl[T any, C CompareFunc[T]] struct {
-	Vector  *Impl[T]
-	compare C
-	kind    OrderKind
+	locker     *orderLocker
+	storage    orderedStorage[T]
+	compare    C
+	kind       OrderKind
+	validation bool
+	duplicates OrderDuplicatePolicy
 }
 
 // MakeOrder creates a new instance of OrderImpl with the given type and compare function
@@ -39,15 +104,20 @@ type OrderImpl[T any, C CompareFunc[T]] struct {
 //
 //	compareFunc: The compare function to use for comparing elements of the order.
 //	kind: The order kind to use.
+//	options: The order construction options (see WithBTree, WithValidation and WithDuplicatePolicy).
 //
 // Returns:
 //
 //	A new instance of OrderImpl.
-func MakeOrder[T any, C CompareFunc[T]](compareFunc C, kind OrderKind) OrderImpl[T, C] {
+func MakeOrder[T any, C CompareFunc[T]](compareFunc C, kind OrderKind, options ...OrderOption) OrderImpl[T, C] {
+	opts := makeOrderOptions(options...)
 	return OrderImpl[T, C]{
-		Vector:  NewVector[T](),
-		compare: compareFunc,
-		kind:    kind,
+		locker:     newOrderLocker(),
+		storage:    newOrderedStorage[T](options...),
+		compare:    compareFunc,
+		kind:       kind,
+		validation: opts.validation,
+		duplicates: opts.duplicates,
 	}
 }
 
@@ -58,32 +128,63 @@ func MakeOrder[T any, C CompareFunc[T]](compareFunc C, kind OrderKind) OrderImpl
 //
 //	compareFunc: The compare function to use for comparing elements of the order.
 //	kind: The order kind to use.
+//	options: The order construction options (see WithBTree, WithValidation and WithDuplicatePolicy).
 //
 // Returns:
 //
 //	A new instance of OrderImpl.
-func NewOrder[T any, C CompareFunc[T]](compareFunc C, kind OrderKind) *OrderImpl[T, C] {
-	ret := MakeOrder[T](compareFunc, kind)
+func NewOrder[T any, C CompareFunc[T]](compareFunc C, kind OrderKind, options ...OrderOption) *OrderImpl[T, C] {
+	ret := MakeOrder[T](compareFunc, kind, options...)
 	return &ret
 }
 
-// WithLocker sets the locker to be used by order and returns the updated order.
+// WithLocker sets the locker to be used by order and returns the updated order. The locker
+// is shared with the order views (see Reversed).
 //
 // locker: a sync.Locker implementation to be used to synchronize access to the order.
 // returns: a pointer to the updated order.
 func (o *OrderImpl[T, C]) WithLocker(locker sync.Locker) *OrderImpl[T, C] {
-	o.Vector.WithLocker(locker)
+	o.locker.Locker = locker
 	return o
 }
 
 // Locker returns the locker to be used by order
 func (o *OrderImpl[T, C]) Locker() sync.Locker {
-	return o.Vector.Locker()
+	return o.locker.Locker
+}
+
+// Vector returns the vector of the order elements. For the vector storage it is the storage
+// itself what uses the order locker, for other storages (and the reverse views) it is a copy.
+//
+// Deprecated: the elements are kept in the storage selected by the construction options and
+// modifying the vector may break the order invariants (see Validate). Use Data, Len and Select.
+func (o *OrderImpl[T, C]) Vector() *Impl[T] {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	if vector, ok := o.storage.(*Impl[T]); ok {
+		vector.locker = o.locker
+		return vector
+	}
+	ret := NewVector[T]()
+	ret.data = append(ret.data, o.storage.Data()...)
+	return ret
 }
 
 // Empty checks if container is empty
 func (o *OrderImpl[T, C]) Empty() bool {
-	return o.Vector.Len() == 0
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.storage.len() == 0
+}
+
+// Len returns the count of order elements
+func (o *OrderImpl[T, C]) Len() int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.storage.len()
 }
 
 // Kind returns an order kind
@@ -91,63 +192,202 @@ func (o *OrderImpl[T, C]) Kind() OrderKind {
 	return o.kind
 }
 
-// Data returns an order data
+// Data returns an order data. The slice may be a copy, depending on the storage. For the vector
+// storage the slice is shared with the order, so it must not be modified and it is valid only
+// until the next order mutation.
 func (o *OrderImpl[T, C]) Data() []T {
-	return o.Vector.Data()
+	return o.storage.Data()
 }
 
-// Add element(s) to order, result is count of added elements
-func (o *OrderImpl[T, C]) add(values ...T) (count uint) {
+// spawn creates a new empty order with the same compare function, kind, storage type, validation mode
+// and duplicate policy
+func (o *OrderImpl[T, C]) spawn() *OrderImpl[T, C] {
+	return &OrderImpl[T, C]{
+		locker:     newOrderLocker(),
+		storage:    o.storage.newEmpty(),
+		compare:    o.compare,
+		kind:       o.kind,
+		validation: o.validation,
+		duplicates: o.duplicates,
+	}
+}
 
-	for _, value := range values {
+// reversed returns the reverse view of the order
+func (o *OrderImpl[T, C]) reversed() *OrderImpl[T, C] {
+	return &OrderImpl[T, C]{
+		locker:     o.locker,
+		storage:    reverseStorage(o.storage),
+		compare:    o.compare,
+		kind:       -o.kind,
+		validation: o.validation,
+		duplicates: o.duplicates,
+	}
+}
 
-		index := sort.Search(o.Vector.len(), func(i int) bool {
-			switch o.kind {
-			case OrderKindIncreasing:
-				return o.compare(o.Vector.get(uint(i)), value) > 0
-			case OrderKindDecreasing:
-				return o.compare(o.Vector.get(uint(i)), value) < 0
-			default:
-				panic("unsupported order kind")
-			}
-		})
+// Reversed returns the view of the order with the opposite kind. Creating the view does not
+// copy elements: it shares the storage and the locker with the order, so the changes made
+// through the view are visible in the order and vice versa. The view Data returns a copy.
+func (o *OrderImpl[T, C]) Reversed() *OrderImpl[T, C] {
+	return o.reversed()
+}
+
+// oriented returns the order itself or its reverse view to have the kind
+func (o *OrderImpl[T, C]) oriented(kind OrderKind) *OrderImpl[T, C] {
+	if o.kind == kind {
+		return o
+	}
+	return o.reversed()
+}
+
+// Resort returns a new order of the same elements sorted with the new compare function and kind.
+// The new order has the same storage type and duplicate policy. The elements equal by the new
+// compare function keep their relative order, only one of them is kept unless the duplicate
+// policy allows duplicates as Repair does.
+func (o *OrderImpl[T, C]) Resort(newCompare C, newKind OrderKind) *OrderImpl[T, C] {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	ret := o.spawn()
+	ret.compare = newCompare
+	ret.kind = newKind
+
+	data := append(make([]T, 0, o.storage.len()), o.storage.Data()...)
+	ret.storage.reset(ret.sorted(data))
+	ret.check()
+
+	return ret
+}
+
+// lockBoth locks order and rhs as LockOrders does, the shared locker (e.g. of the reverse view)
+// is locked once
+func (o *OrderImpl[T, C]) lockBoth(rhs *OrderImpl[T, C]) func() {
+	return LockOrders(o, rhs)
+}
+
+// before checks if lhs is placed before rhs in the order
+func (o *OrderImpl[T, C]) before(lhs, rhs T) bool {
+	switch o.kind {
+	case OrderKindIncreasing:
+		return o.compare(lhs, rhs) < 0
+	case OrderKindDecreasing:
+		return o.compare(lhs, rhs) > 0
+	default:
+		panic("unsupported order kind")
+	}
+}
+
+// lowerBound returns the index of the first element what is not placed before value
+func (o *OrderImpl[T, C]) lowerBound(value T) int {
+	return sort.Search(o.storage.len(), func(i int) bool {
+		return !o.before(o.storage.get(uint(i)), value)
+	})
+}
+
+// upperBound returns the index of the first element what is placed after value
+func (o *OrderImpl[T, C]) upperBound(value T) int {
+	return sort.Search(o.storage.len(), func(i int) bool {
+		return o.before(value, o.storage.get(uint(i)))
+	})
+}
+
+// preferBulk checks if adding count elements by bulkAdd is cheaper than inserting them one by one.
+// The vector insertion copies all elements, so the bulk path is preferred for large batches.
+// Other storages insert in O(log n), rebuilding them is not cheaper.
+func (o *OrderImpl[T, C]) preferBulk(count int) bool {
+	if count <= bulkAddThreshold {
+		return false
+	}
+	_, ok := o.storage.(*Impl[T])
+	return ok
+}
 
-		if index > o.Vector.len()-1 {
-			index = o.Vector.len()
+// bulkAdd sorts the values and merges them with the stored elements in one pass. The result
+// is the same as of adding the values one by one in accordance to the duplicate policy.
+// The merged slice replaces the storage elements.
+func (o *OrderImpl[T, C]) bulkAdd(values ...T) (count uint) {
+	batch := append(make([]T, 0, len(values)), values...)
+	sort.SliceStable(batch, func(i, j int) bool {
+		return o.before(batch[i], batch[j])
+	})
+
+	data := o.storage.Data()
+	merged := make([]T, 0, len(data)+len(batch))
+	dataIndex := 0
+
+	for _, value := range batch {
+		for dataIndex < len(data) && !o.before(value, data[dataIndex]) {
+			merged = append(merged, data[dataIndex])
+			dataIndex++
+		}
+
+		last := len(merged) - 1
+		if o.duplicates == OrderDuplicatesAllow || last < 0 || o.compare(merged[last], value) != 0 {
+			merged = append(merged, value)
+			count++
+			continue
+		}
+		switch o.duplicates {
+		case OrderDuplicatesReject:
+			continue
+		case OrderDuplicatesReplace:
+			merged[last] = value
 		}
+		count++
+	}
+	merged = append(merged, data[dataIndex:]...)
+
+	o.storage.reset(merged)
+	return
+}
+
+// Add element(s) to order in accordance to the duplicate policy, result is count of accepted elements.
+// The large batches are added by bulkAdd.
+func (o *OrderImpl[T, C]) add(values ...T) (count uint) {
+	if o.preferBulk(len(values)) {
+		return o.bulkAdd(values...)
+	}
 
-		o.Vector.insert(uint(index), value)
+	for _, value := range values {
+		if o.duplicates == OrderDuplicatesAllow {
+			o.storage.insert(uint(o.upperBound(value)), value)
+			count++
+			continue
+		}
 
+		index := o.lowerBound(value)
+		if index < o.storage.len() && o.compare(o.storage.get(uint(index)), value) == 0 {
+			switch o.duplicates {
+			case OrderDuplicatesReject:
+				continue
+			case OrderDuplicatesReplace:
+				o.storage.set(uint(index), value)
+			}
+		} else {
+			o.storage.insert(uint(index), value)
+		}
 		count++
 	}
 
 	return
 }
 
-// Add element(s) to order, result is count of added elements
+// Add element(s) to order in accordance to the duplicate policy (see OrderDuplicatePolicy),
+// result is count of accepted elements
 func (o *OrderImpl[T, C]) Add(values ...T) (count uint) {
-	o.Vector.Locker().Lock()
-	defer o.Vector.Locker().Unlock()
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	defer o.check()
 
 	return o.add(values...)
 }
 
 // Find element first occurrence index by value
 func (o *OrderImpl[T, C]) firstIndexOf(value T) int {
-	if o.Vector.len() == 0 {
+	if o.storage.len() == 0 {
 		return -1
 	}
-	index := sort.Search(o.Vector.len(), func(i int) bool {
-		switch o.kind {
-		case OrderKindIncreasing:
-			return o.compare(o.Vector.get(uint(i)), value) >= 0
-		case OrderKindDecreasing:
-			return o.compare(o.Vector.get(uint(i)), value) <= 0
-		default:
-			panic("unsupported order kind")
-		}
-	})
-	if index < o.Vector.len() && o.compare(o.Vector.get(uint(index)), value) == 0 {
+	index := o.lowerBound(value)
+	if index < o.storage.len() && o.compare(o.storage.get(uint(index)), value) == 0 {
 		return index
 	}
 	return -1
@@ -155,109 +395,356 @@ func (o *OrderImpl[T, C]) firstIndexOf(value T) int {
 
 // FirstIndexOf finds an element first occurrence index by value
 func (o *OrderImpl[T, C]) FirstIndexOf(value T) int {
-	o.Vector.Locker().Lock()
-	defer o.Vector.Locker().Unlock()
+	o.locker.Lock()
+	defer o.locker.Unlock()
 
 	return o.firstIndexOf(value)
 }
 
-// Merge orders
-func (o *OrderImpl[T, C]) merge(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
-	ret := NewOrder[T](o.compare, o.kind)
+// Find element last occurrence index by value
+func (o *OrderImpl[T, C]) lastIndexOf(value T) int {
+	lo, hi := o.equalRange(value)
+	if lo == hi {
+		return -1
+	}
+	return hi - 1
+}
 
-	lhsIndex := 0
-	rhsIndex := 0
+// LastIndexOf finds an element last occurrence index by value
+func (o *OrderImpl[T, C]) LastIndexOf(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
 
-	for lhsIndex < o.Vector.len() && rhsIndex < rhs.Vector.len() {
-		lhsValue := o.Vector.get(uint(lhsIndex))
-		rhsValue := rhs.Vector.get(uint(rhsIndex))
+	return o.lastIndexOf(value)
+}
 
-		compareRes := o.compare(lhsValue, rhsValue)
+// equalRange returns the range of elements equal to value
+func (o *OrderImpl[T, C]) equalRange(value T) (lo, hi int) {
+	lo = o.lowerBound(value)
+	hi = lo + sort.Search(o.storage.len()-lo, func(i int) bool {
+		return o.before(value, o.storage.get(uint(lo+i)))
+	})
+	return
+}
 
-		if (o.kind == OrderKindIncreasing && compareRes <= 0) || (o.kind == OrderKindDecreasing && compareRes >= 0) {
-			ret.Vector.append(lhsValue)
-			lhsIndex++
-		} else {
-			ret.Vector.append(rhsValue)
-			rhsIndex++
+// EqualRange returns the range [lo, hi) of elements equal to value. The range is empty
+// (lo == hi) if there are no such elements, lo is the position where value would be added.
+func (o *OrderImpl[T, C]) EqualRange(value T) (lo, hi int) {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.equalRange(value)
+}
+
+// Count returns the count of elements equal to value
+func (o *OrderImpl[T, C]) Count(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	lo, hi := o.equalRange(value)
+	return hi - lo
+}
+
+// remove removes the first element equal to value
+func (o *OrderImpl[T, C]) remove(value T) bool {
+	index := o.firstIndexOf(value)
+	if index == -1 {
+		return false
+	}
+	o.storage.remove(uint(index))
+	return true
+}
+
+// Remove removes the first element equal to value, result is true if the element was removed
+func (o *OrderImpl[T, C]) Remove(value T) bool {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	defer o.check()
+
+	return o.remove(value)
+}
+
+// removeAll removes all elements equal to value
+func (o *OrderImpl[T, C]) removeAll(value T) int {
+	lo, hi := o.equalRange(value)
+	o.storage.removeRange(lo, hi)
+	return hi - lo
+}
+
+// RemoveAll removes all elements equal to value, result is count of removed elements
+func (o *OrderImpl[T, C]) RemoveAll(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	defer o.check()
+
+	return o.removeAll(value)
+}
+
+// removeAt removes the element at index
+func (o *OrderImpl[T, C]) removeAt(index int) T {
+	if index < 0 || index >= o.storage.len() {
+		panic(ErrIndexOutOfRange)
+	}
+	return o.storage.remove(uint(index))
+}
+
+// RemoveAt removes the element at index and returns it. Panics if the index is out of range.
+func (o *OrderImpl[T, C]) RemoveAt(index int) T {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	defer o.check()
+
+	return o.removeAt(index)
+}
+
+// mergeWith merges orders in one pass, the lhs elements are placed before the equal rhs ones.
+// If unique is set, only one of equal elements is kept: the last one for the replace policy
+// and the first one otherwise.
+func (o *OrderImpl[T, C]) mergeWith(rhs *OrderImpl[T, C], unique bool) *OrderImpl[T, C] {
+	ret := o.spawn()
+	rhs = rhs.oriented(o.kind)
+
+	lhsLen := o.storage.len()
+	rhsLen := rhs.storage.len()
+	lhsIndex := 0
+	rhsIndex := 0
+	hasLast := false
+	var last T
+
+	appendValue := func(value T) {
+		if unique && hasLast && o.compare(last, value) == 0 {
+			if o.duplicates == OrderDuplicatesReplace {
+				ret.storage.set(uint(ret.storage.len()-1), value)
+				last = value
+			}
+			return
 		}
+		ret.storage.append(value)
+		last = value
+		hasLast = true
 	}
 
-	if lhsIndex < o.Vector.len() {
-		ret.Vector.append(o.Vector.Data()[lhsIndex:]...)
-	} else if rhsIndex < rhs.Vector.len() {
-		ret.Vector.append(rhs.Vector.Data()[rhsIndex:]...)
+	for lhsIndex < lhsLen || rhsIndex < rhsLen {
+		if rhsIndex < rhsLen {
+			rhsValue := rhs.storage.get(uint(rhsIndex))
+			if lhsIndex >= lhsLen || o.before(rhsValue, o.storage.get(uint(lhsIndex))) {
+				appendValue(rhsValue)
+				rhsIndex++
+				continue
+			}
+		}
+		appendValue(o.storage.get(uint(lhsIndex)))
+		lhsIndex++
 	}
 
 	return ret
 }
 
 // Merge orders
+func (o *OrderImpl[T, C]) merge(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
+	return o.mergeWith(rhs, o.duplicates != OrderDuplicatesAllow)
+}
+
+// Merge orders, the result has the order kind and duplicate policy. The rhs of the opposite
+// kind is walked in the reverse direction. The equal elements are kept in accordance to
+// the duplicate policy: all of them (the lhs ones first) for the allow policy, the last one
+// for the replace policy and the first one otherwise.
 func (o *OrderImpl[T, C]) Merge(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
-	o.Vector.Locker().Lock()
-	rhs.Vector.Locker().Lock()
-	defer func() {
-		rhs.Vector.Locker().Unlock()
-		o.Vector.Locker().Unlock()
-	}()
+	defer o.lockBoth(rhs)()
 
 	return o.merge(rhs)
 }
 
 // Merge orders and omit non unique elements in resulting order
 func (o *OrderImpl[T, C]) combine(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
-	ret := NewOrder[T](o.compare, o.kind)
-	lhsIndex := 0
-	rhsIndex := 0
-	var prevValue *T
+	return o.mergeWith(rhs, true)
+}
 
-	for lhsIndex < o.Vector.len() && rhsIndex < rhs.Vector.len() {
-		lhsValue := o.Vector.get(uint(lhsIndex))
-		rhsValue := rhs.Vector.get(uint(rhsIndex))
+// Combine merges orders and omit non unique elements in resulting order, the result has
+// the order kind. The rhs of the opposite kind is walked in the reverse direction.
+// The last of equal elements is kept for the replace policy and the first one otherwise.
+func (o *OrderImpl[T, C]) Combine(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
+	defer o.lockBoth(rhs)()
 
-		compareRes := o.compare(lhsValue, rhsValue)
+	return o.combine(rhs)
+}
 
-		if (o.kind == OrderKindIncreasing && compareRes <= 0) || (o.kind == OrderKindDecreasing && compareRes >= 0) {
-			if prevValue == nil || o.compare(*prevValue, lhsValue) != 0 {
-				ret.Vector.append(lhsValue)
-				prevValue = &lhsValue
-			}
-			lhsIndex++
-		} else {
-			if prevValue == nil || o.compare(*prevValue, rhsValue) != 0 {
-				ret.Vector.append(rhsValue)
-				prevValue = &rhsValue
+// Rank returns the count of elements placed strictly before value in the order
+func (o *OrderImpl[T, C]) Rank(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.lowerBound(value)
+}
+
+// xselect returns the element at the k position in the order
+func (o *OrderImpl[T, C]) xselect(k int) T {
+	if k < 0 || k >= o.storage.len() {
+		panic(ErrIndexOutOfRange)
+	}
+	return o.storage.get(uint(k))
+}
+
+// Select returns the element at the k (zero based) position in the order, it is the k-th
+// smallest element for the increasing order and the k-th largest for the decreasing one.
+// Panics if k is out of range.
+func (o *OrderImpl[T, C]) Select(k int) T {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.xselect(k)
+}
+
+// countLess returns the count of elements less than value
+func (o *OrderImpl[T, C]) countLess(value T) int {
+	if o.kind == OrderKindDecreasing {
+		return o.storage.len() - o.upperBound(value)
+	}
+	return o.lowerBound(value)
+}
+
+// CountLess returns the count of elements less than value
+func (o *OrderImpl[T, C]) CountLess(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.countLess(value)
+}
+
+// countGreater returns the count of elements greater than value
+func (o *OrderImpl[T, C]) countGreater(value T) int {
+	if o.kind == OrderKindDecreasing {
+		return o.lowerBound(value)
+	}
+	return o.storage.len() - o.upperBound(value)
+}
+
+// CountGreater returns the count of elements greater than value
+func (o *OrderImpl[T, C]) CountGreater(value T) int {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.countGreater(value)
+}
+
+// quantile returns the q quantile of the order elements (nearest rank method)
+func (o *OrderImpl[T, C]) quantile(q float64) T {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		panic(ErrInvalidQuantile)
+	}
+
+	count := o.storage.len()
+	if count == 0 {
+		panic(ErrEmptyVector)
+	}
+
+	index := int(math.Ceil(q*float64(count))) - 1
+	if index < 0 {
+		index = 0
+	}
+	if o.kind == OrderKindDecreasing {
+		index = count - 1 - index
+	}
+
+	return o.storage.get(uint(index))
+}
+
+// Quantile returns the q quantile of the order elements using the nearest rank method,
+// Quantile(0) is the smallest element and Quantile(1) is the largest one.
+// Panics if the order is empty or q is out of [0, 1] range.
+func (o *OrderImpl[T, C]) Quantile(q float64) T {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.quantile(q)
+}
+
+// Median returns the median of the order elements, the lower one for even count of elements.
+// Panics if the order is empty.
+func (o *OrderImpl[T, C]) Median() T {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.quantile(0.5)
+}
+
+// validate checks that every element is not placed before its predecessor and the elements
+// are unique unless the duplicate policy allows duplicates
+func (o *OrderImpl[T, C]) validate() error {
+	var prev T
+	return o.storage.xrange(func(index int, value T) error {
+		if index > 0 {
+			switch {
+			case o.before(value, prev):
+				return fmt.Errorf("element at %d: %w", index, ErrOrderViolation)
+			case o.duplicates != OrderDuplicatesAllow && o.compare(prev, value) == 0:
+				return fmt.Errorf("element at %d: %w", index, ErrDuplicateElement)
 			}
-			rhsIndex++
 		}
+		prev = value
+		return nil
+	})
+}
+
+// Validate checks the order invariants, they may be broken by modifying the storage directly
+// (e.g. through the deprecated Vector accessor).
+//
+// Returns nil or the error wrapping ErrOrderViolation or ErrDuplicateElement with the first
+// violating index.
+func (o *OrderImpl[T, C]) Validate() error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	return o.validate()
+}
+
+// check validates the order in the validation mode and panics on violation
+func (o *OrderImpl[T, C]) check() {
+	if !o.validation {
+		return
 	}
+	if err := o.validate(); err != nil {
+		panic(err)
+	}
+}
+
+// repair sorts the elements, the equal elements keep their relative order. Only one of equal
+// elements is kept unless the duplicate policy allows duplicates: the last one for the replace
+// policy and the first one otherwise.
+func (o *OrderImpl[T, C]) repair() {
+	data := append(make([]T, 0, o.storage.len()), o.storage.Data()...)
+	o.storage.reset(o.sorted(data))
+}
+
+// sorted sorts data in place and removes duplicates in accordance to the duplicate policy as
+// repair does, returns the sorted data
+func (o *OrderImpl[T, C]) sorted(data []T) []T {
+	sort.SliceStable(data, func(i, j int) bool {
+		return o.before(data[i], data[j])
+	})
 
-	var rest []T
-	switch {
-	case lhsIndex >= o.Vector.len() && rhsIndex < rhs.Vector.len():
-		rest = rhs.Vector.Data()[rhsIndex:]
-	case rhsIndex >= rhs.Vector.len() && lhsIndex < o.Vector.len():
-		rest = o.Vector.Data()[lhsIndex:]
+	if o.duplicates == OrderDuplicatesAllow {
+		return data
 	}
-	for _, restValue := range rest {
-		restValue := restValue
-		if prevValue == nil || o.compare(*prevValue, restValue) != 0 {
-			ret.Vector.append(restValue)
-			prevValue = &restValue
+
+	count := 0
+	for _, value := range data {
+		switch {
+		case count == 0 || o.compare(data[count-1], value) != 0:
+			data[count] = value
+			count++
+		case o.duplicates == OrderDuplicatesReplace:
+			data[count-1] = value
 		}
 	}
-
-	return ret
+	return clearTail(data, count)
 }
 
-// Combine merges orders and omit non unique elements in resulting order
-func (o *OrderImpl[T, C]) Combine(rhs *OrderImpl[T, C]) *OrderImpl[T, C] {
-	o.Vector.Locker().Lock()
-	rhs.Vector.Locker().Lock()
-	defer func() {
-		rhs.Vector.Locker().Unlock()
-		o.Vector.Locker().Unlock()
-	}()
+// Repair restores the order invariants by sorting the elements and removing duplicates
+// if the duplicate policy does not allow them.
+func (o *OrderImpl[T, C]) Repair() {
+	o.locker.Lock()
+	defer o.locker.Unlock()
 
-	return o.combine(rhs)
+	o.repair()
 }
@@ -1,6 +1,9 @@
 package vector
 
 import (
+	"fmt"
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -29,3 +32,531 @@ func TestOrder_FirstIndexOf_DecreasingOrder(t *testing.T) {
 	assert.Equal(t, 0, o.FirstIndexOf(36))
 	assert.Equal(t, -1, o.FirstIndexOf(37))
 }
+
+func TestOrder_LastIndexOf(t *testing.T) {
+	o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+	o.Add(33, 36, 34, 34, 34, 35)
+
+	assert.Equal(t, -1, o.LastIndexOf(32))
+	assert.Equal(t, 0, o.LastIndexOf(33))
+	assert.Equal(t, 3, o.LastIndexOf(34))
+	assert.Equal(t, 5, o.LastIndexOf(36))
+
+	d := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindDecreasing, WithBTree(2))
+	d.Add(33, 36, 34, 34, 34, 35)
+
+	assert.Equal(t, 4, d.LastIndexOf(34))
+	assert.Equal(t, 5, d.LastIndexOf(33))
+	assert.Equal(t, -1, d.LastIndexOf(37))
+}
+
+func TestOrder_EqualRange(t *testing.T) {
+	for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind)
+		o.Add(1, 3, 3, 3, 5)
+
+		lo, hi := o.EqualRange(3)
+		assert.Equal(t, 1, lo)
+		assert.Equal(t, 4, hi)
+		assert.Equal(t, 3, o.Count(3))
+		assert.Equal(t, 1, o.Count(5))
+
+		lo, hi = o.EqualRange(4)
+		assert.Equal(t, lo, hi)
+		assert.Equal(t, 0, o.Count(4))
+	}
+}
+
+func TestOrder_Remove(t *testing.T) {
+	for _, options := range [][]OrderOption{nil, {WithBTree(2)}} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, options...)
+		o.Add(5, 1, 3, 3, 3, 7, 9)
+
+		assert.True(t, o.Remove(3))
+		assert.False(t, o.Remove(4))
+		assert.Equal(t, []int{1, 3, 3, 5, 7, 9}, o.Data())
+
+		assert.Equal(t, 2, o.RemoveAll(3))
+		assert.Equal(t, 0, o.RemoveAll(3))
+		assert.Equal(t, []int{1, 5, 7, 9}, o.Data())
+
+		assert.Equal(t, 7, o.RemoveAt(2))
+		assert.Equal(t, []int{1, 5, 9}, o.Data())
+		assert.PanicsWithValue(t, ErrIndexOutOfRange, func() { o.RemoveAt(3) })
+		assert.PanicsWithValue(t, ErrIndexOutOfRange, func() { o.RemoveAt(-1) })
+	}
+}
+
+func TestOrder_Reversed(t *testing.T) {
+	for _, options := range [][]OrderOption{nil, {WithBTree(2)}} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, options...).WithLocker(&sync.Mutex{})
+		o.Add(3, 1, 2, 2)
+
+		r := o.Reversed()
+		assert.Equal(t, OrderKindDecreasing, r.Kind())
+		assert.Equal(t, []int{3, 2, 2, 1}, r.Data())
+		assert.Equal(t, 1, r.FirstIndexOf(2))
+		assert.Equal(t, 3, r.Select(0))
+
+		r.Add(5, 0)
+		assert.Equal(t, []int{5, 3, 2, 2, 1, 0}, r.Data())
+		assert.Equal(t, []int{0, 1, 2, 2, 3, 5}, o.Data())
+
+		assert.Equal(t, 2, r.RemoveAll(2))
+		assert.Equal(t, []int{0, 1, 3, 5}, o.Data())
+
+		assert.Equal(t, OrderKindIncreasing, r.Reversed().Kind())
+		assert.Equal(t, []int{0, 1, 3, 5}, r.Reversed().Data())
+
+		// The view shares the locker, so merging with it must not deadlock.
+		assert.Equal(t, []int{0, 0, 1, 1, 3, 3, 5, 5}, o.Merge(r).Data())
+
+		// The locker set after the view creation is shared with the view.
+		locker := &sync.Mutex{}
+		o.WithLocker(locker)
+		assert.Same(t, locker, r.Locker())
+
+		r.storage.truncate(1)
+		assert.Equal(t, []int{5}, o.Data())
+	}
+}
+
+func TestOrder_Resort(t *testing.T) {
+	o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, WithBTree(2))
+	o.Add(-3, 1, -2, 2, 4)
+
+	abs := func(lhs, rhs int) int {
+		if lhs < 0 {
+			lhs = -lhs
+		}
+		if rhs < 0 {
+			rhs = -rhs
+		}
+		return CompareNumber(lhs, rhs)
+	}
+
+	r := o.Resort(abs, OrderKindDecreasing)
+	assert.Equal(t, OrderKindDecreasing, r.Kind())
+	assert.Equal(t, []int{4, -3, -2, 2, 1}, r.Data())
+	assert.Equal(t, []int{-3, -2, 1, 2, 4}, o.Data())
+
+	r.Add(-5)
+	assert.Equal(t, -5, r.Select(0))
+
+	// The duplicate policy is applied to the elements equal by the new compare function.
+	half := func(lhs, rhs int) int {
+		return CompareNumber(lhs/2, rhs/2)
+	}
+	set := NewSet[int, CompareFunc[int]](CompareNumber[int], WithValidation())
+	set.Add(1, 2, 3, 4)
+	resorted := set.Order.Resort(half, OrderKindIncreasing)
+	assert.Equal(t, []int{1, 2, 4}, resorted.Data())
+	assert.NoError(t, resorted.Validate())
+
+	replace := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, WithDuplicatePolicy(OrderDuplicatesReplace))
+	replace.Add(1, 2, 3, 4)
+	assert.Equal(t, []int{1, 3, 4}, replace.Resort(half, OrderKindIncreasing).Data())
+}
+
+func TestOrder_MergeKinds(t *testing.T) {
+	inc := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+	dec := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindDecreasing)
+	inc.Add(1, 3, 5)
+	dec.Add(2, 3, 6)
+
+	assert.Equal(t, []int{1, 2, 3, 3, 5, 6}, inc.Merge(dec).Data())
+	assert.Equal(t, []int{6, 5, 3, 3, 2, 1}, dec.Merge(inc).Data())
+	assert.Equal(t, []int{1, 2, 3, 5, 6}, inc.Combine(dec).Data())
+	assert.Equal(t, []int{6, 5, 3, 2, 1}, dec.Combine(inc).Data())
+}
+
+func TestOrder_RankSelect(t *testing.T) {
+	increasing := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+	increasing.Add(50, 10, 40, 20, 30, 30)
+
+	assert.Equal(t, 0, increasing.Rank(5))
+	assert.Equal(t, 2, increasing.Rank(30))
+	assert.Equal(t, 6, increasing.Rank(60))
+	assert.Equal(t, 10, increasing.Select(0))
+	assert.Equal(t, 30, increasing.Select(3))
+	assert.Equal(t, 50, increasing.Select(5))
+	assert.Panics(t, func() {
+		increasing.Select(6)
+	})
+
+	decreasing := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindDecreasing)
+	decreasing.Add(50, 10, 40, 20, 30, 30)
+
+	assert.Equal(t, 2, decreasing.Rank(30))
+	assert.Equal(t, 0, decreasing.Rank(60))
+	assert.Equal(t, 50, decreasing.Select(0))
+	assert.Equal(t, 40, decreasing.Select(1))
+	assert.Equal(t, 30, decreasing.Select(decreasing.Rank(30)))
+}
+
+func TestOrder_Count(t *testing.T) {
+	for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind)
+		o.Add(50, 10, 40, 20, 30, 30)
+
+		assert.Equal(t, 2, o.CountLess(30))
+		assert.Equal(t, 2, o.CountGreater(30))
+		assert.Equal(t, 0, o.CountLess(10))
+		assert.Equal(t, 6, o.CountLess(100))
+		assert.Equal(t, 0, o.CountGreater(50))
+		assert.Equal(t, 6, o.CountGreater(0))
+	}
+}
+
+func TestOrder_Quantile(t *testing.T) {
+	for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind)
+		assert.Panics(t, func() {
+			o.Median()
+		})
+
+		o.Add(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+
+		assert.Equal(t, 1, o.Quantile(0))
+		assert.Equal(t, 1, o.Quantile(0.1))
+		assert.Equal(t, 3, o.Quantile(0.25))
+		assert.Equal(t, 9, o.Quantile(0.9))
+		assert.Equal(t, 10, o.Quantile(0.95))
+		assert.Equal(t, 10, o.Quantile(1))
+		assert.Equal(t, 5, o.Median())
+		assert.Panics(t, func() {
+			o.Quantile(1.5)
+		})
+
+		o.Add(11)
+		assert.Equal(t, 6, o.Median())
+	}
+}
+
+func TestOrder_BTree(t *testing.T) {
+	for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+		vector := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind)
+		tree := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind, WithBTree(2))
+
+		random := rand.New(rand.NewSource(1))
+		for i := 0; i < 1000; i++ {
+			value := random.Intn(200)
+			assert.Equal(t, vector.Add(value), tree.Add(value))
+		}
+
+		assert.Equal(t, vector.Data(), tree.Data())
+		assert.Equal(t, 1000, tree.Len())
+		for value := -1; value <= 200; value++ {
+			assert.Equal(t, vector.FirstIndexOf(value), tree.FirstIndexOf(value))
+		}
+
+		rhsVector := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind)
+		rhsTree := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind, WithBTree(2))
+		for i := 0; i < 300; i++ {
+			value := random.Intn(300)
+			rhsVector.Add(value)
+			rhsTree.Add(value)
+		}
+
+		merged := tree.Merge(rhsTree)
+		assert.Equal(t, vector.Merge(rhsVector).Data(), merged.Data())
+		assert.Equal(t, vector.Combine(rhsVector).Data(), tree.Combine(rhsTree).Data())
+
+		// The result of Merge keeps the backend of the order.
+		_, ok := merged.storage.(*bTree[int])
+		assert.True(t, ok)
+	}
+}
+
+func benchmarkOrderAdd(b *testing.B, options ...OrderOption) {
+	for _, count := range []int{10, 100, 1000, 10000} {
+		values := rand.New(rand.NewSource(1)).Perm(count)
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, options...)
+				for _, value := range values {
+					o.add(value)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkOrder_AddVector(b *testing.B) {
+	benchmarkOrderAdd(b)
+}
+
+func BenchmarkOrder_AddBTree(b *testing.B) {
+	benchmarkOrderAdd(b, WithBTree(32))
+}
+
+func TestOrder_MergeAll(t *testing.T) {
+	o0 := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, WithBTree(2))
+	o1 := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing).WithLocker(&sync.Mutex{})
+	o2 := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindDecreasing)
+	o0.Add(1, 4, 7, 7)
+	o1.Add(2, 4, 8)
+	o2.Add(3, 4, 9)
+
+	merged := MergeAll(o0, o1, o2, o1.Reversed())
+	assert.Equal(t, OrderKindIncreasing, merged.Kind())
+	assert.Equal(t, []int{1, 2, 2, 3, 4, 4, 4, 4, 7, 7, 8, 8, 9}, merged.Data())
+	assert.IsType(t, &bTree[int]{}, merged.storage)
+
+	assert.Equal(t, []int{9, 8, 7, 4, 3, 2, 1}, CombineAll(o2, o1, o0).Data())
+	assert.Nil(t, MergeAll[int, CompareFunc[int]]())
+	assert.Nil(t, CombineAll[int, CompareFunc[int]]())
+
+	// The lockers are told apart by the order, so the non comparable lockers are supported.
+	o0.WithLocker(uncomparableLocker{})
+	o2.WithLocker(uncomparableLocker{})
+	assert.Equal(t, []int{1, 3, 4, 4, 7, 7, 9}, MergeAll(o0, o2).Data())
+}
+
+// uncomparableLocker is a locker stub of the non comparable type
+type uncomparableLocker struct {
+	_ []int
+}
+
+// Lock implements sync.Locker.
+func (uncomparableLocker) Lock() {}
+
+// Unlock implements sync.Locker.
+func (uncomparableLocker) Unlock() {}
+
+func TestOrder_MergeIterator(t *testing.T) {
+	o0 := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+	o1 := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+	o0.Add(1, 3, 5)
+	o1.Add(1, 2, 6)
+
+	// The iterator walks the orders in place under the caller locks.
+	defer LockOrders(o0, o1)()
+	iterator := MergeIterator(o0, o1)
+	var got []int
+	for value, ok := iterator.Next(); ok; value, ok = iterator.Next() {
+		got = append(got, value)
+		if len(got) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 1, 2}, got)
+	assert.NoError(t, iterator.Range(func(index int, value int) error {
+		assert.Equal(t, len(got)-3, index)
+		got = append(got, value)
+		return nil
+	}))
+	assert.Equal(t, []int{1, 1, 2, 3, 5, 6}, got)
+
+	got = nil
+	assert.NoError(t, CombineIterator(o0, o1).Range(func(_ int, value int) error {
+		got = append(got, value)
+		return nil
+	}))
+	assert.Equal(t, []int{1, 2, 3, 5, 6}, got)
+
+	_, ok := MergeIterator[int, CompareFunc[int]]().Next()
+	assert.False(t, ok)
+}
+
+func BenchmarkOrder_MergeAll(b *testing.B) {
+	orders := make([]*OrderImpl[int, CompareFunc[int]], 0, 32)
+	for shard := 0; shard < 32; shard++ {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+		for i := 0; i < 1000; i++ {
+			o.storage.append(i*32 + shard)
+		}
+		orders = append(orders, o)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MergeAll(orders...)
+	}
+}
+
+func TestOrder_Vector(t *testing.T) {
+	o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing).WithLocker(&sync.Mutex{})
+	o.Add(3, 1, 2)
+
+	// The vector storage is returned itself and is guarded by the order locker.
+	v := o.Vector()
+	assert.Equal(t, []int{1, 2, 3}, v.Data())
+	v.Set(0, 4)
+	assert.ErrorIs(t, o.Validate(), ErrOrderViolation)
+	o.Repair()
+
+	// Other storages return a copy.
+	b := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, WithBTree(2))
+	b.Add(3, 1, 2)
+	v = b.Vector()
+	assert.Equal(t, []int{1, 2, 3}, v.Data())
+	v.Set(0, 4)
+	assert.Equal(t, []int{1, 2, 3}, b.Data())
+	assert.Equal(t, []int{4, 3, 2}, o.Reversed().Vector().Data())
+}
+
+func TestOrder_ValidateRepair(t *testing.T) {
+	for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], kind, WithBTree(2))
+		o.Add(1, 2, 2, 3)
+		assert.NoError(t, o.Validate())
+
+		o.storage.set(1, 10)
+		err := o.Validate()
+		assert.ErrorIs(t, err, ErrOrderViolation)
+		if kind == OrderKindIncreasing {
+			assert.EqualError(t, err, "element at 2: order violation")
+		} else {
+			assert.EqualError(t, err, "element at 1: order violation")
+		}
+
+		o.Repair()
+		assert.NoError(t, o.Validate())
+		if kind == OrderKindIncreasing {
+			assert.Equal(t, []int{1, 2, 3, 10}, o.Data())
+		} else {
+			assert.Equal(t, []int{10, 3, 2, 1}, o.Data())
+		}
+	}
+}
+
+func TestOrder_Validation(t *testing.T) {
+	o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing, WithValidation())
+	o.Add(3, 1, 2)
+
+	o.storage.set(0, 5)
+	assert.PanicsWithError(t, "element at 1: order violation", func() { o.Add(4) })
+
+	o.Repair()
+	assert.NotPanics(t, func() { o.Remove(4) })
+	assert.True(t, o.spawn().validation)
+}
+
+type policyEntry struct {
+	key   int
+	value string
+}
+
+func comparePolicyEntry(lhs, rhs policyEntry) int {
+	return CompareNumber(lhs.key, rhs.key)
+}
+
+func newPolicyOrder(policy OrderDuplicatePolicy, entries ...policyEntry) *OrderImpl[policyEntry, CompareFunc[policyEntry]] {
+	o := NewOrder[policyEntry, CompareFunc[policyEntry]](comparePolicyEntry, OrderKindIncreasing,
+		WithDuplicatePolicy(policy), WithValidation())
+	o.Add(entries...)
+	return o
+}
+
+func TestOrder_DuplicatePolicyAdd(t *testing.T) {
+	a1 := policyEntry{1, "a1"}
+	a2 := policyEntry{1, "a2"}
+	b := policyEntry{2, "b"}
+
+	allow := newPolicyOrder(OrderDuplicatesAllow)
+	assert.Equal(t, uint(3), allow.Add(a1, b, a2))
+	assert.Equal(t, []policyEntry{a1, a2, b}, allow.Data())
+
+	reject := newPolicyOrder(OrderDuplicatesReject)
+	assert.Equal(t, uint(2), reject.Add(a1, b, a2))
+	assert.Equal(t, []policyEntry{a1, b}, reject.Data())
+
+	replace := newPolicyOrder(OrderDuplicatesReplace)
+	assert.Equal(t, uint(3), replace.Add(a1, b, a2))
+	assert.Equal(t, []policyEntry{a2, b}, replace.Data())
+
+	keepFirst := newPolicyOrder(OrderDuplicatesKeepFirst)
+	assert.Equal(t, uint(3), keepFirst.Add(a1, b, a2))
+	assert.Equal(t, []policyEntry{a1, b}, keepFirst.Data())
+}
+
+func TestOrder_DuplicatePolicyMerge(t *testing.T) {
+	a1 := policyEntry{1, "a1"}
+	a2 := policyEntry{1, "a2"}
+	b := policyEntry{2, "b"}
+	c := policyEntry{3, "c"}
+
+	for _, tc := range []struct {
+		policy  OrderDuplicatePolicy
+		merge   []policyEntry
+		combine []policyEntry
+	}{
+		{OrderDuplicatesAllow, []policyEntry{a1, a2, b, c}, []policyEntry{a1, b, c}},
+		{OrderDuplicatesReject, []policyEntry{a1, b, c}, []policyEntry{a1, b, c}},
+		{OrderDuplicatesReplace, []policyEntry{a2, b, c}, []policyEntry{a2, b, c}},
+		{OrderDuplicatesKeepFirst, []policyEntry{a1, b, c}, []policyEntry{a1, b, c}},
+	} {
+		lhs := newPolicyOrder(tc.policy, a1, c)
+		rhs := newPolicyOrder(tc.policy, a2, b)
+
+		assert.Equal(t, tc.merge, lhs.Merge(rhs).Data())
+		assert.Equal(t, tc.combine, lhs.Combine(rhs).Data())
+		assert.Equal(t, tc.merge, MergeAll(lhs, rhs).Data())
+		assert.Equal(t, tc.combine, CombineAll(lhs, rhs).Data())
+	}
+}
+
+func TestOrder_DuplicatePolicyRepair(t *testing.T) {
+	o := newPolicyOrder(OrderDuplicatesReplace, policyEntry{1, "a"}, policyEntry{2, "b"})
+	o.storage.set(1, policyEntry{1, "c"})
+	assert.ErrorIs(t, o.Validate(), ErrDuplicateElement)
+
+	o.Repair()
+	assert.Equal(t, []policyEntry{{1, "c"}}, o.Data())
+}
+
+func TestOrder_BulkAdd(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	policies := []OrderDuplicatePolicy{
+		OrderDuplicatesAllow, OrderDuplicatesReject, OrderDuplicatesReplace, OrderDuplicatesKeepFirst,
+	}
+
+	for _, policy := range policies {
+		for _, kind := range []OrderKind{OrderKindIncreasing, OrderKindDecreasing} {
+			for _, options := range [][]OrderOption{nil, {WithBTree(2)}} {
+				options = append(options, WithDuplicatePolicy(policy), WithValidation())
+				bulk := NewOrder[policyEntry, CompareFunc[policyEntry]](comparePolicyEntry, kind, options...)
+				single := NewOrder[policyEntry, CompareFunc[policyEntry]](comparePolicyEntry, kind, options...)
+
+				for round := 0; round < 3; round++ {
+					batch := make([]policyEntry, 10*bulkAddThreshold)
+					for i := range batch {
+						batch[i] = policyEntry{random.Intn(100), fmt.Sprint(round, i)}
+					}
+					// The bulk path is taken for the vector storage only.
+					_, vector := bulk.storage.(*Impl[policyEntry])
+					assert.Equal(t, vector, bulk.preferBulk(len(batch)))
+
+					var count uint
+					for _, value := range batch {
+						count += single.Add(value)
+					}
+					assert.Equal(t, count, bulk.Add(batch...))
+					assert.Equal(t, single.Data(), bulk.Data())
+				}
+			}
+		}
+	}
+}
+
+func benchmarkOrderAddBatch(b *testing.B, batchSize int) {
+	random := rand.New(rand.NewSource(1))
+	batch := make([]int, batchSize)
+	for i := range batch {
+		batch[i] = random.Int()
+	}
+
+	for i := 0; i < b.N; i++ {
+		o := NewOrder[int, CompareFunc[int]](CompareNumber[int], OrderKindIncreasing)
+		o.Add(batch...)
+	}
+}
+
+func BenchmarkOrder_AddBatch(b *testing.B) {
+	for _, size := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			benchmarkOrderAddBatch(b, size)
+		})
+	}
+}
@@ -0,0 +1,551 @@
+package vector
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+var (
+	// ErrPersistentQueueClosed raised by persistent queue methods when the queue is closed
+	ErrPersistentQueueClosed = errors.New("persistent queue closed")
+
+	// ErrCorruptedRecord is reported when the log record is damaged
+	ErrCorruptedRecord = errors.New("corrupted record")
+
+	// ErrRecordTooLarge returned by Enqueue when the encoded element exceeds MaxRecordSize
+	ErrRecordTooLarge = errors.New("record too large")
+)
+
+// Codec is an interface of an element codec used by persistent containers
+type Codec[T any] interface {
+	Encode(T) ([]byte, error)
+	Decode([]byte) (T, error)
+}
+
+// JSONCodec is a codec what encodes elements as JSON documents
+type JSONCodec[T any] struct{}
+
+// Encode encodes value as JSON document.
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode decodes value from JSON document.
+func (JSONCodec[T]) Decode(data []byte) (ret T, err error) {
+	err = json.Unmarshal(data, &ret)
+	return
+}
+
+// SyncPolicy defines when persistent queue flushes log to the stable storage
+type SyncPolicy int
+
+const (
+	// SyncPolicyAlways syncs log file after every record
+	SyncPolicyAlways SyncPolicy = iota
+	// SyncPolicyEveryN syncs log file after every N records (see WithSyncEvery)
+	SyncPolicyEveryN
+	// SyncPolicyNever leaves syncing to the operating system
+	SyncPolicyNever
+)
+
+const (
+	// record types of the log
+	recordEnqueue byte = 1
+	recordDequeue byte = 2
+
+	// record header: type (1) + payload length (4) + crc32 (4)
+	recordHeaderSize = 9
+
+	// DefaultCompactThreshold is a default count of dead records what triggers log compaction
+	DefaultCompactThreshold = 1024
+
+	// MaxRecordSize is the max size of the record payload. A record header with the larger
+	// length is treated as a corrupted tail of the log.
+	MaxRecordSize = 64 << 20
+)
+
+// logFile is the log file interface used by the persistent queue, it is implemented by os.File
+type logFile interface {
+	io.WriteSeeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// PersistentQueueImpl is an implementation of queue what keeps write-ahead log
+// of all Enqueue/Dequeue operations in a local segment file.
+type PersistentQueueImpl[T any] struct {
+	Queue            *QueueImpl[T]
+	codec            Codec[T]
+	path             string
+	file             logFile
+	records          int
+	unsynced         int
+	syncPolicy       SyncPolicy
+	syncEvery        int
+	compactThreshold int
+	compactErr       error
+}
+
+// OpenPersistentQueue opens (or creates) the log file at path and recovers queue state from it.
+// A damaged or truncated tail of the log is discarded.
+//
+// path: the log file path.
+// kind: the type of queue (FIFO or LIFO).
+// codec: the element codec.
+// Returns a pointer to the opened queue or an error.
+func OpenPersistentQueue[T any](path string, kind QueueKind, codec Codec[T]) (*PersistentQueueImpl[T], error) {
+	ret := &PersistentQueueImpl[T]{
+		Queue:            NewQueue[T](kind),
+		codec:            codec,
+		path:             path,
+		syncPolicy:       SyncPolicyAlways,
+		syncEvery:        1,
+		compactThreshold: DefaultCompactThreshold,
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	validSize, err := ret.recover(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	if err := file.Truncate(validSize); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(validSize, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	ret.file = file
+	return ret, nil
+}
+
+// WithLocker sets the locker for the queue.
+//
+// locker: the synchronization locker to be used.
+// Returns a pointer to the modified queue.
+func (q *PersistentQueueImpl[T]) WithLocker(locker sync.Locker) *PersistentQueueImpl[T] {
+	q.Queue.WithLocker(locker)
+	return q
+}
+
+// WithSyncPolicy sets the log sync policy.
+//
+// policy: the sync policy to be used.
+// Returns a pointer to the modified queue.
+func (q *PersistentQueueImpl[T]) WithSyncPolicy(policy SyncPolicy) *PersistentQueueImpl[T] {
+	q.syncPolicy = policy
+	return q
+}
+
+// WithSyncEvery sets the records count between syncs for SyncPolicyEveryN policy.
+//
+// count: the records count.
+// Returns a pointer to the modified queue.
+func (q *PersistentQueueImpl[T]) WithSyncEvery(count int) *PersistentQueueImpl[T] {
+	if count < 1 {
+		count = 1
+	}
+	q.syncEvery = count
+	return q
+}
+
+// WithCompactThreshold sets the count of dead records what triggers log compaction.
+// Zero disables automatic compaction.
+//
+// threshold: the dead records count.
+// Returns a pointer to the modified queue.
+func (q *PersistentQueueImpl[T]) WithCompactThreshold(threshold int) *PersistentQueueImpl[T] {
+	q.compactThreshold = threshold
+	return q
+}
+
+// recover replays log records and returns the size of the valid log prefix
+func (q *PersistentQueueImpl[T]) recover(file *os.File) (validSize int64, err error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	reader := bufio.NewReader(file)
+
+	for {
+		kind, payload, size, readErr := readRecord(reader, info.Size()-validSize-recordHeaderSize)
+		if readErr != nil {
+			// EOF, truncated or corrupted tail, the valid prefix is already replayed
+			return validSize, nil
+		}
+
+		switch kind {
+		case recordEnqueue:
+			value, decodeErr := q.codec.Decode(payload)
+			if decodeErr != nil {
+				return 0, fmt.Errorf("decode record at %d: %w", validSize, decodeErr)
+			}
+			q.Queue.enqueue(value)
+		case recordDequeue:
+			if q.Queue.empty() {
+				return 0, fmt.Errorf("dequeue record at %d: %w", validSize, ErrCorruptedRecord)
+			}
+			q.Queue.dequeue()
+		default:
+			return validSize, nil
+		}
+
+		q.records++
+		validSize += size
+	}
+}
+
+// readRecord reads a single record from the log. The record payload length must not exceed
+// MaxRecordSize and the count of bytes left in the log (available), otherwise the record is
+// treated as corrupted without reading its payload.
+func readRecord(reader io.Reader, available int64) (kind byte, payload []byte, size int64, err error) {
+	var header [recordHeaderSize]byte
+	if _, err = io.ReadFull(reader, header[:]); err != nil {
+		return
+	}
+
+	kind = header[0]
+	length := binary.LittleEndian.Uint32(header[1:5])
+	checksum := binary.LittleEndian.Uint32(header[5:9])
+
+	if length > MaxRecordSize || int64(length) > available {
+		err = ErrCorruptedRecord
+		return
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(reader, payload); err != nil {
+		return
+	}
+
+	if recordChecksum(kind, payload) != checksum {
+		err = ErrCorruptedRecord
+		return
+	}
+
+	size = int64(recordHeaderSize) + int64(length)
+	return
+}
+
+// encodeRecord encodes a single log record
+func encodeRecord(kind byte, payload []byte) []byte {
+	ret := make([]byte, recordHeaderSize+len(payload))
+	ret[0] = kind
+	binary.LittleEndian.PutUint32(ret[1:5], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(ret[5:9], recordChecksum(kind, payload))
+	copy(ret[recordHeaderSize:], payload)
+	return ret
+}
+
+// recordChecksum calculates the checksum of a log record
+func recordChecksum(kind byte, payload []byte) uint32 {
+	hash := crc32.NewIEEE()
+	hash.Write([]byte{kind})
+	hash.Write(payload)
+	return hash.Sum32()
+}
+
+// write appends a record to the log and syncs it in accordance to sync policy. On failure
+// the log is truncated back to the offset before the record, so the log keeps matching the
+// queue elements. If the log can't be restored, it is closed.
+func (q *PersistentQueueImpl[T]) write(kind byte, payload []byte) error {
+	if q.file == nil {
+		return ErrPersistentQueueClosed
+	}
+
+	offset, err := q.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	if err := q.append(kind, payload); err != nil {
+		if rollbackErr := q.rollback(offset); rollbackErr != nil {
+			q.file.Close()
+			q.file = nil
+			return fmt.Errorf("rollback log to %d: %v: %w", offset, rollbackErr, err)
+		}
+		return err
+	}
+
+	q.records++
+	return nil
+}
+
+// append writes a record to the log and syncs it in accordance to sync policy
+func (q *PersistentQueueImpl[T]) append(kind byte, payload []byte) error {
+	if _, err := q.file.Write(encodeRecord(kind, payload)); err != nil {
+		return err
+	}
+
+	switch q.syncPolicy {
+	case SyncPolicyAlways:
+		return q.file.Sync()
+	case SyncPolicyEveryN:
+		if q.unsynced+1 >= q.syncEvery {
+			if err := q.file.Sync(); err != nil {
+				return err
+			}
+			q.unsynced = 0
+			return nil
+		}
+	}
+
+	q.unsynced++
+	return nil
+}
+
+// rollback truncates the log to offset and moves the write position there
+func (q *PersistentQueueImpl[T]) rollback(offset int64) error {
+	if err := q.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := q.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+// sync flushes the log to the stable storage
+func (q *PersistentQueueImpl[T]) sync() error {
+	if q.file == nil {
+		return ErrPersistentQueueClosed
+	}
+	if err := q.file.Sync(); err != nil {
+		return err
+	}
+	q.unsynced = 0
+	return nil
+}
+
+// Sync flushes the log to the stable storage.
+func (q *PersistentQueueImpl[T]) Sync() error {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	return q.sync()
+}
+
+// Len returns the number of elements in the queue.
+func (q *PersistentQueueImpl[T]) Len() int {
+	return q.Queue.Len()
+}
+
+// Empty checks if the queue is empty.
+func (q *PersistentQueueImpl[T]) Empty() bool {
+	return q.Queue.Empty()
+}
+
+// enqueue logs and enqueues a value to the queue
+func (q *PersistentQueueImpl[T]) enqueue(value T) error {
+	payload, err := q.codec.Encode(value)
+	if err != nil {
+		return err
+	}
+	if len(payload) > MaxRecordSize {
+		return ErrRecordTooLarge
+	}
+
+	if err := q.write(recordEnqueue, payload); err != nil {
+		return err
+	}
+
+	q.Queue.enqueue(value)
+	return nil
+}
+
+// Enqueue adds an element to the queue. Panics if the log can't be written.
+//
+// value: the element to be added to the queue.
+func (q *PersistentQueueImpl[T]) Enqueue(value T) {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	if err := q.enqueue(value); err != nil {
+		panic(err)
+	}
+}
+
+// dequeue logs, removes and returns the element from the queue. The log compaction failure
+// does not fail the dequeue, see maybeCompact.
+func (q *PersistentQueueImpl[T]) dequeue() (ret T, err error) {
+	if q.Queue.empty() {
+		panic(ErrEmptyQueue)
+	}
+
+	if err = q.write(recordDequeue, nil); err != nil {
+		return
+	}
+
+	ret = q.Queue.dequeue()
+	q.maybeCompact()
+	return
+}
+
+// Dequeue removes and returns the element from the queue.
+// Panics if the queue is empty or the log can't be written.
+//
+// T, the type of the queue elements.
+func (q *PersistentQueueImpl[T]) Dequeue() (ret T) {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	ret, err := q.dequeue()
+	if err != nil {
+		panic(err)
+	}
+	return
+}
+
+// TryDequeue removes and returns the element from the queue.
+// Panics if the log can't be written.
+//
+// Returns the element and true, or zero value and false if the queue is empty.
+func (q *PersistentQueueImpl[T]) TryDequeue() (ret T, ok bool) {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	if q.Queue.empty() {
+		return
+	}
+	ret, err := q.dequeue()
+	if err != nil {
+		panic(err)
+	}
+	return ret, true
+}
+
+// maybeCompact compacts the log when the count of dead records reaches the threshold.
+// On failure the old log is kept, the error is saved for CompactError and the compaction
+// is retried on the next dequeue.
+func (q *PersistentQueueImpl[T]) maybeCompact() {
+	if q.compactThreshold <= 0 || q.records-q.Queue.len() < q.compactThreshold {
+		return
+	}
+	q.compactErr = q.compact()
+}
+
+// CompactError returns the error of the last automatic log compaction or nil if it succeeded.
+// The failed compaction does not affect the queue elements, the old log is kept and the
+// compaction is retried on the next Dequeue.
+func (q *PersistentQueueImpl[T]) CompactError() error {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	return q.compactErr
+}
+
+// compact rewrites the log so it contains only the live elements
+func (q *PersistentQueueImpl[T]) compact() error {
+	if q.file == nil {
+		return ErrPersistentQueueClosed
+	}
+
+	tmpPath := q.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(tmp)
+	data := q.Queue.Vector.Data()
+	count := len(data)
+	for i := 0; i < count; i++ {
+		// The LIFO queue keeps the latest element first, so it has to be replayed backwards.
+		value := data[i]
+		if q.Queue.kind == QueueKindLifo {
+			value = data[count-1-i]
+		}
+		payload, err := q.codec.Encode(value)
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+		if _, err := writer.Write(encodeRecord(recordEnqueue, payload)); err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, q.path); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+
+	q.file.Close()
+	q.file = tmp
+	q.records = count
+	q.unsynced = 0
+
+	// The rename is durable only after the directory is synced.
+	return syncDir(filepath.Dir(q.path))
+}
+
+// syncDir flushes the directory entries to the stable storage
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	syncErr := dir.Sync()
+	closeErr := dir.Close()
+	if syncErr != nil {
+		return syncErr
+	}
+	return closeErr
+}
+
+// Compact rewrites the log so it contains only the live elements.
+func (q *PersistentQueueImpl[T]) Compact() error {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	q.compactErr = q.compact()
+	return q.compactErr
+}
+
+// Close syncs and closes the log file. The queue can't be used after Close.
+func (q *PersistentQueueImpl[T]) Close() error {
+	q.Queue.Vector.Locker().Lock()
+	defer q.Queue.Vector.Locker().Unlock()
+
+	if q.file == nil {
+		return ErrPersistentQueueClosed
+	}
+
+	syncErr := q.file.Sync()
+	closeErr := q.file.Close()
+	q.file = nil
+
+	if syncErr != nil {
+		return syncErr
+	}
+	return closeErr
+}
@@ -0,0 +1,272 @@
+package vector
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentQueue_Recover(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.True(t, q.Empty())
+
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 0, q.Dequeue())
+	assert.Equal(t, 1, q.Dequeue())
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, q.Len())
+	for i := 2; i < 5; i++ {
+		assert.Equal(t, i, q.Dequeue())
+	}
+	assert.True(t, q.Empty())
+	assert.Panics(t, func() {
+		q.Dequeue()
+	})
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_RecoverLifo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[string](path, QueueKindLifo, JSONCodec[string]{})
+	assert.NoError(t, err)
+	q.Enqueue("a")
+	q.Enqueue("b")
+	q.Enqueue("c")
+	assert.Equal(t, "c", q.Dequeue())
+	assert.NoError(t, q.Compact())
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[string](path, QueueKindLifo, JSONCodec[string]{})
+	assert.NoError(t, err)
+	assert.Equal(t, "b", q.Dequeue())
+	assert.Equal(t, "a", q.Dequeue())
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_DamagedTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	assert.NoError(t, q.Close())
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+
+	// Cut off the last byte of the last record.
+	assert.NoError(t, os.Truncate(path, info.Size()-1))
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Len())
+	q.Enqueue(3)
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Dequeue())
+	assert.Equal(t, 3, q.Dequeue())
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_Checksum(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q.Enqueue(1)
+	q.Enqueue(2)
+	assert.NoError(t, q.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, 1, q.Dequeue())
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_RecordLength(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q.Enqueue(1)
+	assert.NoError(t, q.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	valid := len(data)
+
+	// Append the record header with the length over the limit and one past the end of the log.
+	for _, length := range []uint32{MaxRecordSize + 1, 100} {
+		header := make([]byte, recordHeaderSize)
+		header[0] = recordEnqueue
+		binary.LittleEndian.PutUint32(header[1:5], length)
+		assert.NoError(t, os.WriteFile(path, append(data[:valid:valid], header...), 0o644))
+
+		q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, q.Len())
+		assert.NoError(t, q.Close())
+
+		info, err := os.Stat(path)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(valid), info.Size())
+	}
+}
+
+func TestPersistentQueue_CompactFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q.WithCompactThreshold(2)
+
+	// The directory in place of the temporary log makes the compaction fail.
+	assert.NoError(t, os.Mkdir(path+".compact", 0o755))
+
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 0, q.Dequeue())
+	assert.Equal(t, 1, q.Dequeue())
+	assert.Error(t, q.CompactError())
+	assert.Equal(t, 7, q.records)
+
+	assert.NoError(t, os.Remove(path+".compact"))
+	assert.Equal(t, 2, q.Dequeue())
+	assert.NoError(t, q.CompactError())
+	assert.Equal(t, 2, q.records)
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, q.Dequeue())
+	assert.Equal(t, 4, q.Dequeue())
+	assert.NoError(t, q.Close())
+}
+
+// failingLog is a log file what fails the writes (after writing a part of the data) or syncs
+type failingLog struct {
+	logFile
+	failWrite bool
+	failSync  bool
+}
+
+// errLogFailure is the injected log failure
+var errLogFailure = errors.New("log failure")
+
+// Write implements io.Writer.
+func (f *failingLog) Write(data []byte) (int, error) {
+	if f.failWrite {
+		written, _ := f.logFile.Write(data[:len(data)/2])
+		return written, errLogFailure
+	}
+	return f.logFile.Write(data)
+}
+
+// Sync implements logFile.
+func (f *failingLog) Sync() error {
+	if f.failSync {
+		return errLogFailure
+	}
+	return f.logFile.Sync()
+}
+
+func TestPersistentQueue_WriteFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	log := &failingLog{logFile: q.file}
+	q.file = log
+
+	q.Enqueue(1)
+	q.Enqueue(2)
+
+	// The short write is rolled back, the queue is not changed.
+	log.failWrite = true
+	assert.PanicsWithValue(t, errLogFailure, func() { q.Enqueue(3) })
+	assert.PanicsWithValue(t, errLogFailure, func() { q.Dequeue() })
+	assert.Equal(t, 2, q.Len())
+	log.failWrite = false
+
+	// The record written but not synced is rolled back as well.
+	log.failSync = true
+	assert.PanicsWithValue(t, errLogFailure, func() { q.Enqueue(3) })
+	assert.PanicsWithValue(t, errLogFailure, func() { q.Dequeue() })
+	assert.Equal(t, 2, q.Len())
+	log.failSync = false
+
+	q.Enqueue(4)
+	assert.Equal(t, 1, q.Dequeue())
+	assert.Equal(t, 4, q.records)
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, q.Len())
+	assert.Equal(t, 2, q.Dequeue())
+	assert.Equal(t, 4, q.Dequeue())
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	q, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q.WithCompactThreshold(10).WithSyncPolicy(SyncPolicyEveryN).WithSyncEvery(4)
+
+	for i := 0; i < 20; i++ {
+		q.Enqueue(i)
+	}
+	for i := 0; i < 15; i++ {
+		assert.Equal(t, i, q.Dequeue())
+	}
+	assert.Less(t, q.records, 20)
+	assert.NoError(t, q.Close())
+
+	q, err = OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, q.Len())
+	for i := 15; i < 20; i++ {
+		assert.Equal(t, i, q.Dequeue())
+	}
+	assert.NoError(t, q.Close())
+}
+
+func TestPersistentQueue_Closed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	var q Queue[int]
+	pq, err := OpenPersistentQueue[int](path, QueueKindFifo, JSONCodec[int]{})
+	assert.NoError(t, err)
+	q = pq
+	assert.NoError(t, pq.Close())
+
+	assert.ErrorIs(t, pq.Close(), ErrPersistentQueueClosed)
+	assert.Panics(t, func() {
+		q.Enqueue(1)
+	})
+}
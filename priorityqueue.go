@@ -172,3 +172,16 @@ func (pq *PriorityQueueImpl[T]) Dequeue() (ret T) {
 
 	return pq.dequeue()
 }
+
+// TryDequeue removes and returns the highest priority item from the priority queue.
+//
+// Returns the item and true, or zero value and false if the queue is empty.
+func (pq *PriorityQueueImpl[T]) TryDequeue() (ret T, ok bool) {
+	pq.Vector.Locker().Lock()
+	defer pq.Vector.Locker().Unlock()
+
+	if pq.empty() {
+		return
+	}
+	return pq.dequeue(), true
+}
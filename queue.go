@@ -134,3 +134,16 @@ func (q *QueueImpl[T]) Dequeue() (ret T) {
 
 	return q.dequeue()
 }
+
+// TryDequeue removes and returns the first element from the queue.
+//
+// Returns the element and true, or zero value and false if the queue is empty.
+func (q *QueueImpl[T]) TryDequeue() (ret T, ok bool) {
+	q.Vector.Locker().Lock()
+	defer q.Vector.Locker().Unlock()
+
+	if q.empty() {
+		return
+	}
+	return q.dequeue(), true
+}
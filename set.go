@@ -1,6 +1,14 @@
 package vector
 
-import "sync"
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrSetDuplicatePolicy raised by MakeSet and NewSet when the duplicate policy option is not
+// OrderDuplicatesReject
+var ErrSetDuplicatePolicy = errors.New("set duplicate policy must be reject")
 
 // Set is an interface of set
 type Set[T any, C CompareFunc[T]] interface {
@@ -14,19 +22,23 @@ type Set[T any, C CompareFunc[T]] interface {
 	Data() []T
 }
 
-// SetImpl is an implementation of set
+// SetImpl is an implementation of set, it is an increasing Order with the reject duplicate policy
 type SetImpl[T any, C CompareFunc[T]] struct {
-	Order   *OrderImpl[T, C]
-	compare C
+	Order *OrderImpl[T, C]
 }
 
 // MakeSet returns a new SetImpl with a given compare function.
 // It takes in a type T and a CompareFunc C.
-// Returns a SetImpl with a new Order based on the compare function.
-func MakeSet[T any, C CompareFunc[T]](compareFunc C) SetImpl[T, C] {
+// The options select the order storage and the validation mode (see WithBTree and WithValidation).
+// The duplicate policy other than OrderDuplicatesReject panics with ErrSetDuplicatePolicy.
+// Returns a SetImpl with a new increasing Order with the reject duplicate policy.
+func MakeSet[T any, C CompareFunc[T]](compareFunc C, options ...OrderOption) SetImpl[T, C] {
+	if opts := makeOrderOptions(options...); opts.hasDuplicates && opts.duplicates != OrderDuplicatesReject {
+		panic(ErrSetDuplicatePolicy)
+	}
+	options = append(options[:len(options):len(options)], WithDuplicatePolicy(OrderDuplicatesReject))
 	return SetImpl[T, C]{
-		Order:   NewOrder[T](compareFunc, OrderKindIncreasing),
-		compare: compareFunc,
+		Order: NewOrder[T](compareFunc, OrderKindIncreasing, options...),
 	}
 }
 
@@ -34,11 +46,19 @@ func MakeSet[T any, C CompareFunc[T]](compareFunc C) SetImpl[T, C] {
 // T is the type of the elements in the set.
 // C is the type of the compare function.
 // compareFunc is the function used to compare elements.
-func NewSet[T any, C CompareFunc[T]](compareFunc C) *SetImpl[T, C] {
-	ret := MakeSet[T](compareFunc)
+// options select the order storage and the validation mode (see WithBTree and WithValidation).
+func NewSet[T any, C CompareFunc[T]](compareFunc C, options ...OrderOption) *SetImpl[T, C] {
+	ret := MakeSet[T](compareFunc, options...)
 	return &ret
 }
 
+// spawn creates a new empty set with the same compare function and storage type
+func (s *SetImpl[T, C]) spawn() *SetImpl[T, C] {
+	return &SetImpl[T, C]{
+		Order: s.Order.spawn(),
+	}
+}
+
 // WithLocker sets the locker to be used by set and returns the updated set.
 //
 // locker: a sync.Locker implementation to be used to synchronize access to the set.
@@ -48,34 +68,27 @@ func (s *SetImpl[T, C]) WithLocker(locker sync.Locker) *SetImpl[T, C] {
 	return s
 }
 
-// Data returns a set data.
+// Data returns a set data. For the vector storage the slice is shared with the set, so it must
+// not be modified and it is valid only until the next set mutation.
 func (s *SetImpl[T, C]) Data() []T {
 	return s.Order.Data()
 }
 
 // Empty checks if the set is empty.
 func (s *SetImpl[T, C]) Empty() bool {
-	return s.Order.Vector.Len() == 0
+	return s.Order.Len() == 0
 }
 
 // Add elements to the set.
 func (s *SetImpl[T, C]) add(values ...T) (count int) {
-
-	for _, v := range values {
-		index := s.Order.firstIndexOf(v)
-		if index == -1 {
-			s.Order.add(v)
-			count++
-		}
-	}
-
-	return count
+	return int(s.Order.add(values...))
 }
 
 // Add elements to the set.
 func (s *SetImpl[T, C]) Add(values ...T) (count int) {
 	s.Order.Locker().Lock()
 	defer s.Order.Locker().Unlock()
+	defer s.Order.check()
 
 	return s.add(values...)
 }
@@ -84,9 +97,7 @@ func (s *SetImpl[T, C]) Add(values ...T) (count int) {
 func (s *SetImpl[T, C]) remove(values ...T) (count int) {
 
 	for _, v := range values {
-		index := s.Order.firstIndexOf(v)
-		if index != -1 {
-			s.Order.Vector.remove(uint(index))
+		if s.Order.remove(v) {
 			count++
 		}
 	}
@@ -98,6 +109,7 @@ func (s *SetImpl[T, C]) remove(values ...T) (count int) {
 func (s *SetImpl[T, C]) Remove(values ...T) (count int) {
 	s.Order.Locker().Lock()
 	defer s.Order.Locker().Unlock()
+	defer s.Order.check()
 
 	return s.remove(values...)
 }
@@ -107,20 +119,14 @@ func (s *SetImpl[T, C]) Range(callback func(index int, value T) error) error {
 	s.Order.Locker().Lock()
 	defer s.Order.Locker().Unlock()
 
-	return s.Order.Vector.xrange(callback)
+	return s.Order.storage.xrange(callback)
 }
 
 // Union constructs a new set of the elements what are available in both original sets.
 func (s *SetImpl[T, C]) Union(rhs *SetImpl[T, C]) *SetImpl[T, C] {
+	defer s.Order.lockBoth(rhs.Order)()
 
-	s.Order.Locker().Lock()
-	rhs.Order.Locker().Lock()
-	defer func() {
-		rhs.Order.Locker().Unlock()
-		s.Order.Locker().Unlock()
-	}()
-
-	ret := NewSet[T](s.compare)
+	ret := s.spawn()
 
 	//ret.add(s.Data()...)
 	//ret.add(rhs.Data()...)
@@ -137,24 +143,13 @@ func (s *SetImpl[T, C]) Union(rhs *SetImpl[T, C]) *SetImpl[T, C] {
 // |xxxxxx|   |      |
 // +------+---+------+
 func (s *SetImpl[T, C]) LeftDifference(rhs *SetImpl[T, C]) *SetImpl[T, C] {
+	defer s.Order.lockBoth(rhs.Order)()
 
-	s.Order.Locker().Lock()
-	rhs.Order.Locker().Lock()
-	defer func() {
-		rhs.Order.Locker().Unlock()
-		s.Order.Locker().Unlock()
-	}()
-
-	ret := NewSet[T](s.compare)
-
-	var values []T
-	for _, v := range s.Data() {
-		rhsIndex := rhs.Order.firstIndexOf(v)
-		if rhsIndex == -1 {
-			values = append(values, v)
-		}
-	}
-	ret.add(values...)
+	ret := s.spawn()
+	s.walk(rhs, func(value T) bool {
+		ret.Order.storage.append(value)
+		return true
+	}, nil, nil)
 
 	return ret
 }
@@ -167,24 +162,13 @@ func (s *SetImpl[T, C]) LeftDifference(rhs *SetImpl[T, C]) *SetImpl[T, C] {
 // |      |   |xxxxxx|
 // +------+---+------+
 func (s *SetImpl[T, C]) RightDifference(rhs *SetImpl[T, C]) *SetImpl[T, C] {
+	defer s.Order.lockBoth(rhs.Order)()
 
-	s.Order.Locker().Lock()
-	rhs.Order.Locker().Lock()
-	defer func() {
-		rhs.Order.Locker().Unlock()
-		s.Order.Locker().Unlock()
-	}()
-
-	ret := NewSet[T](s.compare)
-
-	var values []T
-	for _, v := range rhs.Data() {
-		lhsIndex := s.Order.firstIndexOf(v)
-		if lhsIndex == -1 {
-			values = append(values, v)
-		}
-	}
-	ret.add(values...)
+	ret := s.spawn()
+	s.walk(rhs, nil, nil, func(value T) bool {
+		ret.Order.storage.append(value)
+		return true
+	})
 
 	return ret
 }
@@ -197,30 +181,13 @@ func (s *SetImpl[T, C]) RightDifference(rhs *SetImpl[T, C]) *SetImpl[T, C] {
 // |      |xxx|      |
 // +------+---+------+
 func (s *SetImpl[T, C]) Intersection(rhs *SetImpl[T, C]) *SetImpl[T, C] {
+	defer s.Order.lockBoth(rhs.Order)()
 
-	s.Order.Locker().Lock()
-	rhs.Order.Locker().Lock()
-	defer func() {
-		rhs.Order.Locker().Unlock()
-		s.Order.Locker().Unlock()
-	}()
-
-	ret := NewSet[T](s.compare)
-
-	var values []T
-	for _, v := range rhs.Data() {
-		lhsIndex := s.Order.firstIndexOf(v)
-		if lhsIndex != -1 {
-			values = append(values, v)
-		}
-	}
-	for _, v := range s.Data() {
-		rhsIndex := rhs.Order.firstIndexOf(v)
-		if rhsIndex != -1 {
-			values = append(values, v)
-		}
-	}
-	ret.add(values...)
+	ret := s.spawn()
+	s.walk(rhs, nil, func(value T) bool {
+		ret.Order.storage.append(value)
+		return true
+	}, nil)
 
 	return ret
 }
@@ -262,3 +229,459 @@ func (s *SetImpl[T, C]) HasAll(values ...T) bool {
 
 	return len(values) == counter
 }
+
+// walk passes both sorted sets in one merge pass and calls onlyLhs, both and onlyRhs
+// callbacks for the elements what are only in set, in both sets and only in rhs.
+// Nil callbacks are skipped. A callback returns false to stop the walk.
+func (s *SetImpl[T, C]) walk(rhs *SetImpl[T, C], onlyLhs, both, onlyRhs func(T) bool) {
+	lhsStorage := s.Order.storage
+	rhsStorage := rhs.Order.storage
+	lhsLen := lhsStorage.len()
+	rhsLen := rhsStorage.len()
+	lhsIndex := 0
+	rhsIndex := 0
+
+	call := func(callback func(T) bool, value T) bool {
+		return callback == nil || callback(value)
+	}
+
+	for lhsIndex < lhsLen && rhsIndex < rhsLen {
+		lhsValue := lhsStorage.get(uint(lhsIndex))
+		rhsValue := rhsStorage.get(uint(rhsIndex))
+		compareRes := s.Order.compare(lhsValue, rhsValue)
+		switch {
+		case compareRes < 0:
+			if !call(onlyLhs, lhsValue) {
+				return
+			}
+			lhsIndex++
+		case compareRes > 0:
+			if !call(onlyRhs, rhsValue) {
+				return
+			}
+			rhsIndex++
+		default:
+			if !call(both, lhsValue) {
+				return
+			}
+			lhsIndex++
+			rhsIndex++
+		}
+	}
+
+	if onlyLhs != nil {
+		for ; lhsIndex < lhsLen; lhsIndex++ {
+			if !onlyLhs(lhsStorage.get(uint(lhsIndex))) {
+				return
+			}
+		}
+	}
+	if onlyRhs != nil {
+		for ; rhsIndex < rhsLen; rhsIndex++ {
+			if !onlyRhs(rhsStorage.get(uint(rhsIndex))) {
+				return
+			}
+		}
+	}
+}
+
+// SymmetricDifference constructs a new set of the elements what are available in exactly one of sets:
+// +------+---+------+
+// |xxxxxx|   |xxxxxx|
+// | s xxx|   | rhs x|
+// |   xxx|   |     x|
+// |xxxxxx|   |xxxxxx|
+// +------+---+------+
+func (s *SetImpl[T, C]) SymmetricDifference(rhs *SetImpl[T, C]) *SetImpl[T, C] {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	ret := s.spawn()
+	appendValue := func(value T) bool {
+		ret.Order.storage.append(value)
+		return true
+	}
+	s.walk(rhs, appendValue, nil, appendValue)
+
+	return ret
+}
+
+// isSubsetOf checks if all set elements are available in rhs
+func (s *SetImpl[T, C]) isSubsetOf(rhs *SetImpl[T, C]) bool {
+	if s.Order.storage.len() > rhs.Order.storage.len() {
+		return false
+	}
+
+	ret := true
+	s.walk(rhs, func(T) bool {
+		ret = false
+		return false
+	}, nil, nil)
+
+	return ret
+}
+
+// IsSubsetOf checks if all set elements are available in rhs.
+func (s *SetImpl[T, C]) IsSubsetOf(rhs *SetImpl[T, C]) bool {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	return s.isSubsetOf(rhs)
+}
+
+// IsProperSubsetOf checks if all set elements are available in rhs and rhs has more elements.
+func (s *SetImpl[T, C]) IsProperSubsetOf(rhs *SetImpl[T, C]) bool {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	return s.Order.storage.len() < rhs.Order.storage.len() && s.isSubsetOf(rhs)
+}
+
+// IsSupersetOf checks if all rhs elements are available in set.
+func (s *SetImpl[T, C]) IsSupersetOf(rhs *SetImpl[T, C]) bool {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	return rhs.isSubsetOf(s)
+}
+
+// IsDisjoint checks if sets have no common elements.
+func (s *SetImpl[T, C]) IsDisjoint(rhs *SetImpl[T, C]) bool {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	ret := true
+	s.walk(rhs, nil, func(T) bool {
+		ret = false
+		return false
+	}, nil)
+
+	return ret
+}
+
+// Equal checks if sets contain the same elements.
+func (s *SetImpl[T, C]) Equal(rhs *SetImpl[T, C]) bool {
+	defer s.Order.lockBoth(rhs.Order)()
+
+	return s.Order.storage.len() == rhs.Order.storage.len() && s.isSubsetOf(rhs)
+}
+
+// UnionWith adds all rhs elements to the set in place.
+func (s *SetImpl[T, C]) UnionWith(rhs *SetImpl[T, C]) {
+	defer s.Order.lockBoth(rhs.Order)()
+	defer s.Order.check()
+
+	if rhs.Order.storage.len() == 0 {
+		return
+	}
+
+	data := make([]T, 0, s.Order.storage.len()+rhs.Order.storage.len())
+	appendValue := func(value T) bool {
+		data = append(data, value)
+		return true
+	}
+	s.walk(rhs, appendValue, appendValue, appendValue)
+
+	s.Order.storage.reset(data)
+}
+
+// retain keeps the set elements what are available (common is true) or not available
+// (common is false) in rhs. The kept elements are collected into a new slice, so the slices
+// returned by Data before are not modified.
+func (s *SetImpl[T, C]) retain(rhs *SetImpl[T, C], common bool) {
+	data := make([]T, 0, s.Order.storage.len())
+	rhsLen := rhs.Order.storage.len()
+	rhsIndex := 0
+
+	s.Order.storage.xrange(func(_ int, value T) error {
+		for rhsIndex < rhsLen && s.Order.compare(rhs.Order.storage.get(uint(rhsIndex)), value) < 0 {
+			rhsIndex++
+		}
+		found := rhsIndex < rhsLen && s.Order.compare(rhs.Order.storage.get(uint(rhsIndex)), value) == 0
+		if found == common {
+			data = append(data, value)
+		}
+		return nil
+	})
+
+	s.Order.storage.reset(data)
+}
+
+// IntersectWith removes in place the set elements what are not available in rhs.
+func (s *SetImpl[T, C]) IntersectWith(rhs *SetImpl[T, C]) {
+	defer s.Order.lockBoth(rhs.Order)()
+	defer s.Order.check()
+
+	s.retain(rhs, true)
+}
+
+// SubtractWith removes in place the set elements what are available in rhs.
+func (s *SetImpl[T, C]) SubtractWith(rhs *SetImpl[T, C]) {
+	defer s.Order.lockBoth(rhs.Order)()
+	defer s.Order.check()
+
+	s.retain(rhs, false)
+}
+
+// Min returns the smallest set element. Panics if the set is empty.
+func (s *SetImpl[T, C]) Min() T {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	if s.Order.storage.len() == 0 {
+		panic(ErrEmptyVector)
+	}
+	return s.Order.storage.get(0)
+}
+
+// Max returns the largest set element. Panics if the set is empty.
+func (s *SetImpl[T, C]) Max() T {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	if s.Order.storage.len() == 0 {
+		panic(ErrEmptyVector)
+	}
+	return s.Order.storage.get(uint(s.Order.storage.len() - 1))
+}
+
+// PopMin removes and returns the smallest set element. Panics if the set is empty.
+func (s *SetImpl[T, C]) PopMin() T {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+	defer s.Order.check()
+
+	return s.Order.storage.remove(0)
+}
+
+// PopMax removes and returns the largest set element. Panics if the set is empty.
+func (s *SetImpl[T, C]) PopMax() T {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+	defer s.Order.check()
+
+	count := s.Order.storage.len()
+	if count == 0 {
+		panic(ErrEmptyVector)
+	}
+	ret := s.Order.storage.get(uint(count - 1))
+	s.Order.storage.truncate(count - 1)
+	return ret
+}
+
+// at returns the element at index if the index is in range
+func (s *SetImpl[T, C]) at(index int) (ret T, ok bool) {
+	if index < 0 || index >= s.Order.storage.len() {
+		return
+	}
+	return s.Order.storage.get(uint(index)), true
+}
+
+// Floor returns the largest set element what is less than or equal to value.
+func (s *SetImpl[T, C]) Floor(value T) (T, bool) {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	return s.at(s.Order.upperBound(value) - 1)
+}
+
+// Ceiling returns the smallest set element what is greater than or equal to value.
+func (s *SetImpl[T, C]) Ceiling(value T) (T, bool) {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	return s.at(s.Order.lowerBound(value))
+}
+
+// Lower returns the largest set element what is strictly less than value.
+func (s *SetImpl[T, C]) Lower(value T) (T, bool) {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	return s.at(s.Order.lowerBound(value) - 1)
+}
+
+// Higher returns the smallest set element what is strictly greater than value.
+func (s *SetImpl[T, C]) Higher(value T) (T, bool) {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	return s.at(s.Order.upperBound(value))
+}
+
+// RangeBetween constructs a new set of the elements between lo and hi. The bounds
+// are included into the result when loInclusive and hiInclusive are set.
+func (s *SetImpl[T, C]) RangeBetween(lo T, loInclusive bool, hi T, hiInclusive bool) *SetImpl[T, C] {
+	s.Order.Locker().Lock()
+	defer s.Order.Locker().Unlock()
+
+	first := s.Order.upperBound(lo)
+	if loInclusive {
+		first = s.Order.lowerBound(lo)
+	}
+	last := s.Order.lowerBound(hi)
+	if hiInclusive {
+		last = s.Order.upperBound(hi)
+	}
+
+	ret := s.spawn()
+	for index := first; index < last; index++ {
+		ret.Order.storage.append(s.Order.storage.get(uint(index)))
+	}
+
+	return ret
+}
+
+// Rank returns the count of set elements less than value.
+func (s *SetImpl[T, C]) Rank(value T) int {
+	return s.Order.Rank(value)
+}
+
+// Select returns the k-th (zero based) smallest set element. Panics if k is out of range.
+func (s *SetImpl[T, C]) Select(k int) T {
+	return s.Order.Select(k)
+}
+
+// CountLess returns the count of set elements less than value.
+func (s *SetImpl[T, C]) CountLess(value T) int {
+	return s.Order.CountLess(value)
+}
+
+// CountGreater returns the count of set elements greater than value.
+func (s *SetImpl[T, C]) CountGreater(value T) int {
+	return s.Order.CountGreater(value)
+}
+
+// Median returns the median of the set elements, the lower one for even count of elements.
+// Panics if the set is empty.
+func (s *SetImpl[T, C]) Median() T {
+	return s.Order.Median()
+}
+
+// Quantile returns the q quantile of the set elements using the nearest rank method.
+// Panics if the set is empty or q is out of [0, 1] range.
+func (s *SetImpl[T, C]) Quantile(q float64) T {
+	return s.Order.Quantile(q)
+}
+
+// Validate checks the set invariants, they may be broken by modifying the storage directly
+// (e.g. through the deprecated OrderImpl.Vector accessor).
+//
+// Returns nil or the error wrapping ErrOrderViolation or ErrDuplicateElement with the first
+// violating index.
+func (s *SetImpl[T, C]) Validate() error {
+	return s.Order.Validate()
+}
+
+// Repair restores the set invariants by sorting the elements and removing duplicates,
+// the first of equal elements is kept.
+func (s *SetImpl[T, C]) Repair() {
+	s.Order.Repair()
+}
+
+// setsOrders returns the order of every set
+func setsOrders[T any, C CompareFunc[T]](sets ...*SetImpl[T, C]) []*OrderImpl[T, C] {
+	ret := make([]*OrderImpl[T, C], 0, len(sets))
+	for _, set := range sets {
+		ret = append(ret, set.Order)
+	}
+	return ret
+}
+
+// setsStorages returns the storage of every set
+func setsStorages[T any, C CompareFunc[T]](sets ...*SetImpl[T, C]) []orderedStorage[T] {
+	ret := make([]orderedStorage[T], 0, len(sets))
+	for _, set := range sets {
+		ret = append(ret, set.Order.storage)
+	}
+	return ret
+}
+
+// UnionAll constructs a new set of the elements what are available in any of sets using
+// a single k-way merge. The result has the storage type of the first set.
+// Returns nil if there are no sets.
+func UnionAll[T any, C CompareFunc[T]](sets ...*SetImpl[T, C]) *SetImpl[T, C] {
+	if len(sets) == 0 {
+		return nil
+	}
+	defer LockOrders(setsOrders(sets...)...)()
+
+	first := sets[0]
+	ret := first.spawn()
+	merger := newKWayMerger(first.Order.before, setsStorages(sets...)...)
+
+	hasLast := false
+	var last T
+	for value, ok := merger.next(); ok; value, ok = merger.next() {
+		if hasLast && first.Order.compare(last, value) == 0 {
+			continue
+		}
+		ret.Order.storage.append(value)
+		last = value
+		hasLast = true
+	}
+
+	return ret
+}
+
+// IntersectAll constructs a new set of the elements what are available in all sets.
+// The sets are walked smallest first: the elements of the smallest set are candidates
+// and every other set is searched from its previous position, so the walk stops as soon
+// as any set is exhausted. The result has the storage type of the first set.
+// Returns nil if there are no sets.
+func IntersectAll[T any, C CompareFunc[T]](sets ...*SetImpl[T, C]) *SetImpl[T, C] {
+	if len(sets) == 0 {
+		return nil
+	}
+	defer LockOrders(setsOrders(sets...)...)()
+
+	first := sets[0]
+	ret := first.spawn()
+
+	storages := setsStorages(sets...)
+	sort.SliceStable(storages, func(i, j int) bool {
+		return storages[i].len() < storages[j].len()
+	})
+
+	positions := make([]int, len(storages))
+	for candidate := 0; candidate < storages[0].len(); candidate++ {
+		value := storages[0].get(uint(candidate))
+		found := true
+		for index := 1; index < len(storages) && found; index++ {
+			storage := storages[index]
+			position := positions[index]
+			position += sort.Search(storage.len()-position, func(i int) bool {
+				return first.Order.compare(storage.get(uint(position+i)), value) >= 0
+			})
+			positions[index] = position
+			if position >= storage.len() {
+				return ret
+			}
+			found = first.Order.compare(storage.get(uint(position)), value) == 0
+		}
+		if found {
+			ret.Order.storage.append(value)
+		}
+	}
+
+	return ret
+}
+
+// DifferenceAll constructs a new set of the base elements what are not available in any
+// of others. The others are walked by a single k-way merge. The result has the storage
+// type of the base set.
+func DifferenceAll[T any, C CompareFunc[T]](base *SetImpl[T, C], others ...*SetImpl[T, C]) *SetImpl[T, C] {
+	defer LockOrders(setsOrders(append([]*SetImpl[T, C]{base}, others...)...)...)()
+
+	ret := base.spawn()
+	merger := newKWayMerger(base.Order.before, setsStorages(others...)...)
+
+	base.Order.storage.xrange(func(_ int, value T) error {
+		other, ok := merger.peek()
+		for ok && base.Order.compare(other, value) < 0 {
+			merger.next()
+			other, ok = merger.peek()
+		}
+		if !ok || base.Order.compare(other, value) != 0 {
+			ret.Order.storage.append(value)
+		}
+		return nil
+	})
+
+	return ret
+}
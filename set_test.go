@@ -1,6 +1,8 @@
 package vector
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +13,17 @@ func TestSet_NewSet(t *testing.T) {
 	assert.True(t, s.Empty())
 }
 
+func TestSet_DuplicatePolicy(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int], WithDuplicatePolicy(OrderDuplicatesReject))
+	assert.Equal(t, 1, s.Add(1, 1))
+
+	for _, policy := range []OrderDuplicatePolicy{OrderDuplicatesAllow, OrderDuplicatesReplace, OrderDuplicatesKeepFirst} {
+		assert.PanicsWithValue(t, ErrSetDuplicatePolicy, func() {
+			NewSet[int, CompareFunc[int]](CompareNumber[int], WithDuplicatePolicy(policy))
+		})
+	}
+}
+
 func TestSet_Add(t *testing.T) {
 
 	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
@@ -84,3 +97,362 @@ func TestStringsSet(t *testing.T) {
 	assert.True(t, s.Has("second"))
 	assert.False(t, s.Has("third"))
 }
+
+func TestSet_SymmetricDifference(t *testing.T) {
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+
+	s0.Add(1, 2, 3, 4, 5)
+	s1.Add(4, 5, 6, 7, 8)
+
+	assert.Equal(t, []int{1, 2, 3, 6, 7, 8}, s0.SymmetricDifference(s1).Data())
+	assert.Equal(t, []int{1, 2, 3, 6, 7, 8}, s1.SymmetricDifference(s0).Data())
+	assert.True(t, s0.SymmetricDifference(NewSet[int, CompareFunc[int]](CompareNumber[int])).Equal(s0))
+}
+
+func TestSet_Relations(t *testing.T) {
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s2 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	empty := NewSet[int, CompareFunc[int]](CompareNumber[int])
+
+	s0.Add(2, 4)
+	s1.Add(1, 2, 3, 4, 5)
+	s2.Add(6, 7)
+
+	assert.True(t, s0.IsSubsetOf(s1))
+	assert.True(t, s0.IsSubsetOf(s0))
+	assert.False(t, s1.IsSubsetOf(s0))
+	assert.True(t, empty.IsSubsetOf(s0))
+	assert.True(t, s0.IsProperSubsetOf(s1))
+	assert.False(t, s1.IsProperSubsetOf(s1.Union(s0)))
+	assert.True(t, s1.IsSupersetOf(s0))
+	assert.False(t, s0.IsSupersetOf(s1))
+	assert.True(t, s0.IsDisjoint(s2))
+	assert.False(t, s0.IsDisjoint(s1))
+	assert.True(t, empty.IsDisjoint(s1))
+	assert.True(t, s1.Equal(s1.Union(s0)))
+	assert.False(t, s1.Equal(s0))
+	assert.True(t, empty.Equal(NewSet[int, CompareFunc[int]](CompareNumber[int])))
+}
+
+func TestSet_InPlace(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int]).WithLocker(&sync.Mutex{})
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+
+	s.Add(1, 2, 3, 4, 5)
+	s0.Add(4, 5, 6, 7, 8)
+	s1.Add(0, 2, 5, 7)
+
+	s.UnionWith(s0)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, s.Data())
+
+	s.SubtractWith(s1)
+	assert.Equal(t, []int{1, 3, 4, 6, 8}, s.Data())
+
+	s.IntersectWith(s0)
+	assert.Equal(t, []int{4, 6, 8}, s.Data())
+
+	s.IntersectWith(s1)
+	assert.True(t, s.Empty())
+
+	// The set keeps working after in place modifications.
+	assert.Equal(t, 2, s.Add(3, 1))
+	assert.Equal(t, []int{1, 3}, s.Data())
+}
+
+func TestSet_InPlaceKeepsData(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	rhs := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(1, 2, 3, 4, 5)
+	rhs.Add(2, 4)
+
+	// The slice taken before the in place modification is not changed by it.
+	data := s.Data()
+	s.IntersectWith(rhs)
+	assert.Equal(t, []int{2, 4}, s.Data())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, data)
+
+	data = s.Data()
+	s.SubtractWith(rhs)
+	assert.True(t, s.Empty())
+	assert.Equal(t, []int{2, 4}, data)
+}
+
+// newBenchmarkSet builds a set of count elements starting at first with the given step
+func newBenchmarkSet(first, step, count int) *SetImpl[int, CompareFunc[int]] {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	for i := 0; i < count; i++ {
+		s.Order.storage.append(first + i*step)
+	}
+	return s
+}
+
+func benchmarkSetOperation(b *testing.B, operation func(lhs, rhs *SetImpl[int, CompareFunc[int]]) *SetImpl[int, CompareFunc[int]]) {
+	for _, count := range []int{100000, 1000000} {
+		lhs := newBenchmarkSet(0, 2, count)
+		rhs := newBenchmarkSet(0, 3, count)
+		b.Run(fmt.Sprintf("%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				operation(lhs, rhs)
+			}
+		})
+	}
+}
+
+func BenchmarkSet_Intersection(b *testing.B) {
+	benchmarkSetOperation(b, (*SetImpl[int, CompareFunc[int]]).Intersection)
+}
+
+func BenchmarkSet_LeftDifference(b *testing.B) {
+	benchmarkSetOperation(b, (*SetImpl[int, CompareFunc[int]]).LeftDifference)
+}
+
+func BenchmarkSet_RightDifference(b *testing.B) {
+	benchmarkSetOperation(b, (*SetImpl[int, CompareFunc[int]]).RightDifference)
+}
+
+func TestSet_MinMax(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	assert.Panics(t, func() {
+		s.Min()
+	})
+	assert.Panics(t, func() {
+		s.PopMax()
+	})
+
+	s.Add(5, 1, 9, 3)
+	assert.Equal(t, 1, s.Min())
+	assert.Equal(t, 9, s.Max())
+	assert.Equal(t, 1, s.PopMin())
+	assert.Equal(t, 9, s.PopMax())
+	assert.Equal(t, []int{3, 5}, s.Data())
+}
+
+func TestSet_Navigation(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(10, 20, 30)
+
+	type testCase struct {
+		value   int
+		floor   []int
+		ceiling []int
+		lower   []int
+		higher  []int
+	}
+
+	// Empty slice means there is no such element.
+	testCases := []testCase{
+		{value: 5, floor: nil, ceiling: []int{10}, lower: nil, higher: []int{10}},
+		{value: 10, floor: []int{10}, ceiling: []int{10}, lower: nil, higher: []int{20}},
+		{value: 15, floor: []int{10}, ceiling: []int{20}, lower: []int{10}, higher: []int{20}},
+		{value: 30, floor: []int{30}, ceiling: []int{30}, lower: []int{20}, higher: nil},
+		{value: 35, floor: []int{30}, ceiling: nil, lower: []int{30}, higher: nil},
+	}
+
+	check := func(expected []int, value int, ok bool) {
+		if expected == nil {
+			assert.False(t, ok)
+			return
+		}
+		assert.True(t, ok)
+		assert.Equal(t, expected[0], value)
+	}
+
+	for _, tc := range testCases {
+		value, ok := s.Floor(tc.value)
+		check(tc.floor, value, ok)
+		value, ok = s.Ceiling(tc.value)
+		check(tc.ceiling, value, ok)
+		value, ok = s.Lower(tc.value)
+		check(tc.lower, value, ok)
+		value, ok = s.Higher(tc.value)
+		check(tc.higher, value, ok)
+	}
+}
+
+func TestSet_RangeBetween(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(1, 2, 3, 4, 5, 6)
+
+	assert.Equal(t, []int{2, 3, 4, 5}, s.RangeBetween(2, true, 5, true).Data())
+	assert.Equal(t, []int{3, 4}, s.RangeBetween(2, false, 5, false).Data())
+	assert.Equal(t, []int{1, 2}, s.RangeBetween(0, false, 3, false).Data())
+	assert.True(t, s.RangeBetween(5, true, 2, true).Empty())
+	assert.True(t, s.RangeBetween(3, false, 3, true).Empty())
+
+	// The range is a copy.
+	r := s.RangeBetween(1, true, 2, true)
+	r.Add(0)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, s.Data())
+}
+
+func TestSet_RankSelect(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(40, 10, 30, 20)
+
+	assert.Equal(t, 2, s.Rank(30))
+	assert.Equal(t, 2, s.Rank(25))
+	assert.Equal(t, 30, s.Select(2))
+	assert.Equal(t, 1, s.CountLess(20))
+	assert.Equal(t, 2, s.CountGreater(20))
+	assert.Equal(t, 20, s.Median())
+	assert.Equal(t, 40, s.Quantile(0.9))
+}
+
+func TestSet_BTreeStorage(t *testing.T) {
+	newSets := func(values ...int) (*SetImpl[int, CompareFunc[int]], *SetImpl[int, CompareFunc[int]]) {
+		vector := NewSet[int, CompareFunc[int]](CompareNumber[int])
+		tree := NewSet[int, CompareFunc[int]](CompareNumber[int], WithBTree(2))
+		vector.Add(values...)
+		tree.Add(values...)
+		return vector, tree
+	}
+
+	lhsVector, lhsTree := newSets(9, 1, 8, 2, 7, 3, 6, 4, 5, 5, 1)
+	rhsVector, rhsTree := newSets(4, 5, 6, 10, 11, 12, 0)
+
+	assert.Equal(t, lhsVector.Data(), lhsTree.Data())
+	assert.Equal(t, lhsVector.Union(rhsVector).Data(), lhsTree.Union(rhsTree).Data())
+	assert.Equal(t, lhsVector.Intersection(rhsVector).Data(), lhsTree.Intersection(rhsTree).Data())
+	assert.Equal(t, lhsVector.LeftDifference(rhsVector).Data(), lhsTree.LeftDifference(rhsTree).Data())
+	assert.Equal(t, lhsVector.SymmetricDifference(rhsVector).Data(), lhsTree.SymmetricDifference(rhsTree).Data())
+	assert.Equal(t, lhsVector.RangeBetween(2, true, 7, false).Data(), lhsTree.RangeBetween(2, true, 7, false).Data())
+
+	// The results of set operations keep the storage type.
+	_, ok := lhsTree.Intersection(rhsTree).Order.storage.(*bTree[int])
+	assert.True(t, ok)
+
+	assert.Equal(t, lhsVector.PopMax(), lhsTree.PopMax())
+	assert.Equal(t, lhsVector.PopMin(), lhsTree.PopMin())
+	assert.Equal(t, lhsVector.Remove(3, 4), lhsTree.Remove(3, 4))
+
+	lhsVector.SubtractWith(rhsVector)
+	lhsTree.SubtractWith(rhsTree)
+	assert.Equal(t, lhsVector.Data(), lhsTree.Data())
+
+	lhsVector.UnionWith(rhsVector)
+	lhsTree.UnionWith(rhsTree)
+	assert.Equal(t, lhsVector.Data(), lhsTree.Data())
+
+	lhsVector.IntersectWith(rhsVector)
+	lhsTree.IntersectWith(rhsTree)
+	assert.Equal(t, lhsVector.Data(), lhsTree.Data())
+	assert.Equal(t, rhsVector.Data(), lhsTree.Data())
+}
+
+func TestSet_UnionAll(t *testing.T) {
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int]).WithLocker(&sync.Mutex{})
+	s2 := NewSet[int, CompareFunc[int]](CompareNumber[int], WithBTree(2))
+
+	s0.Add(1, 4, 7)
+	s1.Add(2, 4, 8)
+	s2.Add(3, 4, 9, 10)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 7, 8, 9, 10}, UnionAll(s0, s1, s2).Data())
+	assert.Equal(t, []int{1, 2, 4, 7, 8}, UnionAll(s0, s1, s1).Data())
+	assert.Equal(t, []int{1, 4, 7}, UnionAll(s0).Data())
+	assert.Nil(t, UnionAll[int, CompareFunc[int]]())
+}
+
+func TestSet_IntersectAll(t *testing.T) {
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s2 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	empty := NewSet[int, CompareFunc[int]](CompareNumber[int])
+
+	s0.Add(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	s1.Add(2, 3, 5, 7, 9, 11)
+	s2.Add(3, 5, 9, 12)
+
+	assert.Equal(t, []int{3, 5, 9}, IntersectAll(s0, s1, s2).Data())
+	assert.Equal(t, []int{2, 3, 5, 7, 9}, IntersectAll(s1, s0).Data())
+	assert.True(t, IntersectAll(s0, empty, s1).Empty())
+	assert.Nil(t, IntersectAll[int, CompareFunc[int]]())
+}
+
+func TestSet_DifferenceAll(t *testing.T) {
+	base := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s0 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s1 := NewSet[int, CompareFunc[int]](CompareNumber[int])
+
+	base.Add(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	s0.Add(0, 2, 4)
+	s1.Add(4, 6, 10)
+
+	assert.Equal(t, []int{1, 3, 5, 7, 8, 9}, DifferenceAll(base, s0, s1).Data())
+	assert.Equal(t, base.Data(), DifferenceAll(base).Data())
+	assert.True(t, DifferenceAll(base, base).Empty())
+}
+
+func TestSet_ValidateRepair(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(1, 2, 3, 4)
+	assert.NoError(t, s.Validate())
+
+	s.Data()[2] = 2
+	assert.EqualError(t, s.Validate(), "element at 2: duplicate element")
+	assert.ErrorIs(t, s.Validate(), ErrDuplicateElement)
+
+	s.Data()[0] = 5
+	assert.ErrorIs(t, s.Validate(), ErrOrderViolation)
+
+	s.Repair()
+	assert.NoError(t, s.Validate())
+	assert.Equal(t, []int{2, 4, 5}, s.Data())
+}
+
+func TestSet_Validation(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int], WithBTree(2), WithValidation())
+	s.Add(1, 2, 3)
+	assert.True(t, s.Union(s).Order.validation)
+
+	s.Order.storage.set(2, 2)
+	assert.PanicsWithError(t, "element at 2: duplicate element", func() { s.Remove(5) })
+
+	s.Repair()
+	assert.NotPanics(t, func() { s.Add(0) })
+	assert.Equal(t, []int{0, 1, 2}, s.Data())
+}
+
+// recordingLocker records the lock calls of named lockers
+type recordingLocker struct {
+	name   string
+	record *[]string
+}
+
+// Lock implements sync.Locker.
+func (l recordingLocker) Lock() {
+	*l.record = append(*l.record, l.name)
+}
+
+// Unlock implements sync.Locker.
+func (l recordingLocker) Unlock() {}
+
+func TestSet_LockOrder(t *testing.T) {
+	var record []string
+	a := NewSet[int, CompareFunc[int]](CompareNumber[int]).WithLocker(recordingLocker{"a", &record})
+	b := NewSet[int, CompareFunc[int]](CompareNumber[int]).WithLocker(recordingLocker{"b", &record})
+
+	// The sets are locked in the same order whatever the arguments order is.
+	UnionAll(a, b)
+	UnionAll(b, a)
+	a.Union(b)
+	b.Union(a)
+	assert.Equal(t, []string{"a", "b", "a", "b", "a", "b", "a", "b"}, record)
+}
+
+func TestSet_BulkAdd(t *testing.T) {
+	s := NewSet[int, CompareFunc[int]](CompareNumber[int], WithValidation())
+	s.Add(5, 50)
+
+	batch := make([]int, 0, 200)
+	for i := 0; i < 100; i++ {
+		batch = append(batch, i, i)
+	}
+	assert.Equal(t, 98, s.Add(batch...))
+	assert.Equal(t, 100, s.Order.Len())
+	assert.Equal(t, 0, s.Add(batch...))
+}
@@ -0,0 +1,320 @@
+package vector
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// skipListMaxLevel is the max count of skip list levels
+const skipListMaxLevel = 32
+
+// skipListNode is a node of the concurrent skip list
+type skipListNode[T any] struct {
+	sync.Mutex
+	value       T
+	next        []atomic.Pointer[skipListNode[T]]
+	marked      atomic.Bool
+	fullyLinked atomic.Bool
+}
+
+// newSkipListNode creates a node of levels count
+func newSkipListNode[T any](value T, levels int) *skipListNode[T] {
+	return &skipListNode[T]{
+		value: value,
+		next:  make([]atomic.Pointer[skipListNode[T]], levels),
+	}
+}
+
+// levels returns the count of node levels
+func (n *skipListNode[T]) levels() int {
+	return len(n.next)
+}
+
+// SkipListSetImpl is an implementation of the sorted set what many goroutines can read and
+// write in parallel. It is a lazy skip list: the lookups and iteration take no locks, the
+// writers lock only the nodes around the changed one. The iteration is weakly consistent:
+// it observes the elements in order and tolerates concurrent modification, the elements
+// added or removed during the iteration may or may not be observed.
+type SkipListSetImpl[T any, C CompareFunc[T]] struct {
+	head    *skipListNode[T]
+	compare C
+	size    atomic.Int64
+	seed    atomic.Uint64
+}
+
+// NewSkipListSet returns a new empty SkipListSetImpl instance.
+//
+// compareFunc: the function used to compare elements.
+// Returns a pointer to the new SkipListSetImpl.
+func NewSkipListSet[T any, C CompareFunc[T]](compareFunc C) *SkipListSetImpl[T, C] {
+	var zero T
+	return &SkipListSetImpl[T, C]{
+		head:    newSkipListNode(zero, skipListMaxLevel),
+		compare: compareFunc,
+	}
+}
+
+// randomLevels returns the count of levels of a new node, every next level is taken
+// with probability 1/2
+func (s *SkipListSetImpl[T, C]) randomLevels() int {
+	x := mix64(s.seed.Add(0x9e3779b97f4a7c15))
+	ret := 1 + bits.TrailingZeros64(x)
+	if ret > skipListMaxLevel {
+		ret = skipListMaxLevel
+	}
+	return ret
+}
+
+// find fills the predecessors and successors of value at every level and returns
+// the highest level where the node of value is found or -1
+func (s *SkipListSetImpl[T, C]) find(value T, preds, succs []*skipListNode[T]) int {
+	found := -1
+	pred := s.head
+	for level := skipListMaxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && s.compare(curr.value, value) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+		if found == -1 && curr != nil && s.compare(curr.value, value) == 0 {
+			found = level
+		}
+		preds[level] = pred
+		succs[level] = curr
+	}
+	return found
+}
+
+// unlockPreds unlocks the distinct predecessors locked up to level
+func unlockPreds[T any](preds []*skipListNode[T], level int) {
+	var prev *skipListNode[T]
+	for index := 0; index <= level; index++ {
+		if preds[index] != prev {
+			preds[index].Unlock()
+			prev = preds[index]
+		}
+	}
+}
+
+// add adds the value and returns true if it was not in the set
+func (s *SkipListSetImpl[T, C]) add(value T) bool {
+	levels := 0
+	var preds, succs [skipListMaxLevel]*skipListNode[T]
+
+	for {
+		if found := s.find(value, preds[:], succs[:]); found != -1 {
+			node := succs[found]
+			if !node.marked.Load() {
+				for !node.fullyLinked.Load() {
+					runtime.Gosched()
+				}
+				return false
+			}
+			// The node is being removed, retry when it is unlinked.
+			continue
+		}
+		if levels == 0 {
+			levels = s.randomLevels()
+		}
+
+		locked := -1
+		valid := true
+		var prev *skipListNode[T]
+		for level := 0; valid && level < levels; level++ {
+			pred := preds[level]
+			succ := succs[level]
+			if pred != prev {
+				pred.Lock()
+				prev = pred
+			}
+			locked = level
+			valid = !pred.marked.Load() &&
+				(succ == nil || !succ.marked.Load()) &&
+				pred.next[level].Load() == succ
+		}
+		if !valid {
+			unlockPreds(preds[:], locked)
+			continue
+		}
+
+		node := newSkipListNode(value, levels)
+		for level := 0; level < levels; level++ {
+			node.next[level].Store(succs[level])
+		}
+		for level := 0; level < levels; level++ {
+			preds[level].next[level].Store(node)
+		}
+		node.fullyLinked.Store(true)
+		s.size.Add(1)
+		unlockPreds(preds[:], locked)
+		return true
+	}
+}
+
+// remove removes the value and returns true if it was in the set
+func (s *SkipListSetImpl[T, C]) remove(value T) bool {
+	var preds, succs [skipListMaxLevel]*skipListNode[T]
+	var victim *skipListNode[T]
+
+	for {
+		found := s.find(value, preds[:], succs[:])
+		if victim == nil {
+			if found == -1 {
+				return false
+			}
+			node := succs[found]
+			if !node.fullyLinked.Load() || node.levels()-1 != found || node.marked.Load() {
+				return false
+			}
+			node.Lock()
+			if node.marked.Load() {
+				node.Unlock()
+				return false
+			}
+			node.marked.Store(true)
+			victim = node
+		}
+
+		locked := -1
+		valid := true
+		var prev *skipListNode[T]
+		for level := 0; valid && level < victim.levels(); level++ {
+			pred := preds[level]
+			if pred != prev {
+				pred.Lock()
+				prev = pred
+			}
+			locked = level
+			valid = !pred.marked.Load() && pred.next[level].Load() == victim
+		}
+		if !valid {
+			unlockPreds(preds[:], locked)
+			continue
+		}
+
+		for level := victim.levels() - 1; level >= 0; level-- {
+			preds[level].next[level].Store(victim.next[level].Load())
+		}
+		s.size.Add(-1)
+		victim.Unlock()
+		unlockPreds(preds[:], locked)
+		return true
+	}
+}
+
+// has checks if the set contains the value
+func (s *SkipListSetImpl[T, C]) has(value T) bool {
+	pred := s.head
+	for level := skipListMaxLevel - 1; level >= 0; level-- {
+		curr := pred.next[level].Load()
+		for curr != nil && s.compare(curr.value, value) < 0 {
+			pred = curr
+			curr = pred.next[level].Load()
+		}
+		if curr != nil && s.compare(curr.value, value) == 0 {
+			return curr.fullyLinked.Load() && !curr.marked.Load()
+		}
+	}
+	return false
+}
+
+// Len returns the count of the set elements. It is an estimate if the set is modified concurrently.
+func (s *SkipListSetImpl[T, C]) Len() int {
+	return int(s.size.Load())
+}
+
+// Empty checks if the set is empty.
+func (s *SkipListSetImpl[T, C]) Empty() bool {
+	return s.Len() == 0
+}
+
+// Add elements to the set. Every element is added atomically.
+//
+// Returns the count of added elements.
+func (s *SkipListSetImpl[T, C]) Add(values ...T) (count int) {
+	for _, value := range values {
+		if s.add(value) {
+			count++
+		}
+	}
+	return
+}
+
+// Remove elements from the set. Every element is removed atomically.
+//
+// Returns the count of removed elements.
+func (s *SkipListSetImpl[T, C]) Remove(values ...T) (count int) {
+	for _, value := range values {
+		if s.remove(value) {
+			count++
+		}
+	}
+	return
+}
+
+// Has checks if the set contains the value. It takes no locks.
+func (s *SkipListSetImpl[T, C]) Has(value T) bool {
+	return s.has(value)
+}
+
+// HasAny checks if the set contains any of the values.
+func (s *SkipListSetImpl[T, C]) HasAny(values ...T) bool {
+	for _, value := range values {
+		if s.has(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll checks if the set contains all of the values.
+func (s *SkipListSetImpl[T, C]) HasAll(values ...T) bool {
+	for _, value := range values {
+		if !s.has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// xrange calls the callback for the elements starting from the node
+func (s *SkipListSetImpl[T, C]) xrange(node *skipListNode[T], callback func(index int, value T) error) error {
+	index := 0
+	for ; node != nil; node = node.next[0].Load() {
+		if !node.fullyLinked.Load() || node.marked.Load() {
+			continue
+		}
+		if err := callback(index, node.value); err != nil {
+			return err
+		}
+		index++
+	}
+	return nil
+}
+
+// Range enumerates set elements in the increasing order. It takes no locks, so the
+// callback may modify the set. If the callback returns an error, the iteration stops
+// and returns the error.
+func (s *SkipListSetImpl[T, C]) Range(callback func(index int, value T) error) error {
+	return s.xrange(s.head.next[0].Load(), callback)
+}
+
+// RangeFrom enumerates set elements what are greater than or equal to from in the increasing
+// order. It behaves as Range otherwise, the index of the first enumerated element is zero.
+func (s *SkipListSetImpl[T, C]) RangeFrom(from T, callback func(index int, value T) error) error {
+	var preds, succs [skipListMaxLevel]*skipListNode[T]
+	s.find(from, preds[:], succs[:])
+	return s.xrange(succs[0], callback)
+}
+
+// Data returns the set elements in the increasing order.
+func (s *SkipListSetImpl[T, C]) Data() []T {
+	ret := make([]T, 0, s.Len())
+	s.Range(func(_ int, value T) error {
+		ret = append(ret, value)
+		return nil
+	})
+	return ret
+}
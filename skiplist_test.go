@@ -0,0 +1,120 @@
+package vector
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipListSet(t *testing.T) {
+	var s Set[int, CompareFunc[int]] = NewSkipListSet[int, CompareFunc[int]](CompareNumber[int])
+	assert.True(t, s.Empty())
+
+	assert.Equal(t, 5, s.Add(5, 1, 4, 2, 3, 4))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, s.Data())
+	assert.True(t, s.Has(3))
+	assert.False(t, s.Has(6))
+	assert.True(t, s.HasAny(0, 5))
+	assert.False(t, s.HasAll(1, 6))
+	assert.True(t, s.HasAll(1, 5))
+
+	assert.Equal(t, 2, s.Remove(1, 3, 6))
+	assert.Equal(t, []int{2, 4, 5}, s.Data())
+
+	err := errors.New("stop")
+	assert.ErrorIs(t, s.Range(func(int, int) error { return err }), err)
+}
+
+func TestSkipListSet_RangeFrom(t *testing.T) {
+	s := NewSkipListSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(10, 20, 30, 40)
+
+	var got []int
+	assert.NoError(t, s.RangeFrom(15, func(index int, value int) error {
+		assert.Equal(t, len(got), index)
+		got = append(got, value)
+		return nil
+	}))
+	assert.Equal(t, []int{20, 30, 40}, got)
+}
+
+func TestSkipListSet_RangeMutation(t *testing.T) {
+	s := NewSkipListSet[int, CompareFunc[int]](CompareNumber[int])
+	s.Add(1, 2, 3, 4, 5)
+
+	var got []int
+	assert.NoError(t, s.Range(func(_ int, value int) error {
+		got = append(got, value)
+		// The removed elements ahead are skipped, the added ones ahead are observed.
+		s.Remove(value + 1)
+		if value < 10 {
+			s.Add(value + 10)
+		}
+		return nil
+	}))
+	assert.Equal(t, []int{1, 3, 5, 11, 13, 15}, got)
+	assert.Equal(t, []int{1, 3, 5, 11, 13, 15}, s.Data())
+}
+
+func TestSkipListSet_Race(t *testing.T) {
+	const (
+		workers   = 8
+		perWorker = 2000
+	)
+
+	s := NewSkipListSet[int, CompareFunc[int]](CompareNumber[int])
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				// The own values are added once, the shared ones are contended.
+				own := perWorker + i*workers + worker
+				assert.Equal(t, 1, s.Add(own))
+				s.Add(i)
+				if i%2 == 0 {
+					s.Remove(i)
+				}
+				assert.True(t, s.Has(own))
+			}
+		}(worker)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			prev := -1
+			s.Range(func(_ int, value int) error {
+				assert.Less(t, prev, value)
+				prev = value
+				return nil
+			})
+		}
+	}()
+	wg.Wait()
+
+	data := s.Data()
+	assert.Equal(t, len(data), s.Len())
+	for i := 0; i < perWorker; i++ {
+		assert.Equal(t, i%2 != 0, s.Has(i))
+	}
+	for index := 1; index < len(data); index++ {
+		assert.Less(t, data[index-1], data[index])
+	}
+}
+
+func BenchmarkSkipListSet(b *testing.B) {
+	s := NewSkipListSet[int, CompareFunc[int]](CompareNumber[int])
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			s.Add(i % 1024)
+			s.Has(i % 512)
+			i++
+		}
+	})
+}
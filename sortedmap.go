@@ -0,0 +1,177 @@
+package vector
+
+import "sync"
+
+// SortedMapEntry is a key/value pair of sorted map
+type SortedMapEntry[K any, V any] struct {
+	Key   K
+	Value V
+}
+
+// SortedMapImpl is an implementation of the ordered dictionary, the entries are
+// kept in the increasing order of keys.
+type SortedMapImpl[K any, V any, C CompareFunc[K]] struct {
+	Order   *OrderImpl[SortedMapEntry[K, V], CompareFunc[SortedMapEntry[K, V]]]
+	compare C
+}
+
+// MakeSortedMap returns a new SortedMapImpl with a given keys compare function.
+//
+// compareFunc: the function used to compare keys.
+// Returns a new SortedMapImpl.
+func MakeSortedMap[K any, V any, C CompareFunc[K]](compareFunc C) SortedMapImpl[K, V, C] {
+	entryCompare := func(lhs, rhs SortedMapEntry[K, V]) int {
+		return compareFunc(lhs.Key, rhs.Key)
+	}
+	return SortedMapImpl[K, V, C]{
+		Order:   NewOrder[SortedMapEntry[K, V]](CompareFunc[SortedMapEntry[K, V]](entryCompare), OrderKindIncreasing),
+		compare: compareFunc,
+	}
+}
+
+// NewSortedMap returns a new SortedMapImpl instance.
+//
+// compareFunc: the function used to compare keys.
+// Returns a pointer to the new SortedMapImpl.
+func NewSortedMap[K any, V any, C CompareFunc[K]](compareFunc C) *SortedMapImpl[K, V, C] {
+	ret := MakeSortedMap[K, V](compareFunc)
+	return &ret
+}
+
+// WithLocker sets the locker to be used by map and returns the updated map.
+//
+// locker: a sync.Locker implementation to be used to synchronize access to the map.
+// returns: a pointer to the updated map.
+func (m *SortedMapImpl[K, V, C]) WithLocker(locker sync.Locker) *SortedMapImpl[K, V, C] {
+	m.Order.WithLocker(locker)
+	return m
+}
+
+// Len returns the count of map entries.
+func (m *SortedMapImpl[K, V, C]) Len() int {
+	return m.Order.Len()
+}
+
+// Empty checks if the map is empty.
+func (m *SortedMapImpl[K, V, C]) Empty() bool {
+	return m.Order.Empty()
+}
+
+// indexOf returns the index of the key entry or -1
+func (m *SortedMapImpl[K, V, C]) indexOf(key K) int {
+	return m.Order.firstIndexOf(SortedMapEntry[K, V]{Key: key})
+}
+
+// Put sets the value for the key.
+//
+// Returns true if the new entry was added and false if the existing value was replaced.
+func (m *SortedMapImpl[K, V, C]) Put(key K, value V) bool {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	index := m.indexOf(key)
+	if index != -1 {
+		m.Order.storage.set(uint(index), SortedMapEntry[K, V]{Key: key, Value: value})
+		return false
+	}
+
+	m.Order.add(SortedMapEntry[K, V]{Key: key, Value: value})
+	return true
+}
+
+// Get returns the value for the key.
+//
+// Returns the value and true, or zero value and false if there is no such key.
+func (m *SortedMapImpl[K, V, C]) Get(key K) (ret V, ok bool) {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	index := m.indexOf(key)
+	if index == -1 {
+		return
+	}
+	return m.Order.storage.get(uint(index)).Value, true
+}
+
+// Has checks if the map contains the key.
+func (m *SortedMapImpl[K, V, C]) Has(key K) bool {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.indexOf(key) != -1
+}
+
+// Delete removes the key entry from the map.
+//
+// Returns true if the entry was removed.
+func (m *SortedMapImpl[K, V, C]) Delete(key K) bool {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	index := m.indexOf(key)
+	if index == -1 {
+		return false
+	}
+	m.Order.storage.remove(uint(index))
+	return true
+}
+
+// Keys returns the sorted map keys.
+func (m *SortedMapImpl[K, V, C]) Keys() []K {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	ret := make([]K, 0, m.Order.storage.len())
+	for _, entry := range m.Order.storage.Data() {
+		ret = append(ret, entry.Key)
+	}
+	return ret
+}
+
+// Values returns the map values in the order of keys.
+func (m *SortedMapImpl[K, V, C]) Values() []V {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	ret := make([]V, 0, m.Order.storage.len())
+	for _, entry := range m.Order.storage.Data() {
+		ret = append(ret, entry.Value)
+	}
+	return ret
+}
+
+// Range enumerates map entries in the order of keys. If the callback returns an error,
+// the iteration stops and returns the error.
+func (m *SortedMapImpl[K, V, C]) Range(callback func(key K, value V) error) error {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.Order.storage.xrange(func(_ int, entry SortedMapEntry[K, V]) error {
+		return callback(entry.Key, entry.Value)
+	})
+}
+
+// at returns the entry at index if the index is in range
+func (m *SortedMapImpl[K, V, C]) at(index int) (key K, value V, ok bool) {
+	if index < 0 || index >= m.Order.storage.len() {
+		return
+	}
+	entry := m.Order.storage.get(uint(index))
+	return entry.Key, entry.Value, true
+}
+
+// Floor returns the entry with the largest key what is less than or equal to key.
+func (m *SortedMapImpl[K, V, C]) Floor(key K) (K, V, bool) {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.at(m.Order.upperBound(SortedMapEntry[K, V]{Key: key}) - 1)
+}
+
+// Ceiling returns the entry with the smallest key what is greater than or equal to key.
+func (m *SortedMapImpl[K, V, C]) Ceiling(key K) (K, V, bool) {
+	m.Order.Locker().Lock()
+	defer m.Order.Locker().Unlock()
+
+	return m.at(m.Order.lowerBound(SortedMapEntry[K, V]{Key: key}))
+}
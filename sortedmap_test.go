@@ -0,0 +1,79 @@
+package vector
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedMap(t *testing.T) {
+	m := NewSortedMap[string, int, CompareFunc[string]](CompareString[string]).WithLocker(&sync.Mutex{})
+	assert.True(t, m.Empty())
+
+	assert.True(t, m.Put("b", 2))
+	assert.True(t, m.Put("c", 3))
+	assert.True(t, m.Put("a", 1))
+	assert.False(t, m.Put("b", 20))
+	assert.Equal(t, 3, m.Len())
+
+	value, ok := m.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, 20, value)
+	_, ok = m.Get("d")
+	assert.False(t, ok)
+	assert.True(t, m.Has("a"))
+
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	assert.Equal(t, []int{1, 20, 3}, m.Values())
+
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+	assert.Equal(t, []string{"b", "c"}, m.Keys())
+}
+
+func TestSortedMap_Range(t *testing.T) {
+	m := NewSortedMap[int, string, CompareFunc[int]](CompareNumber[int])
+	m.Put(3, "three")
+	m.Put(1, "one")
+	m.Put(2, "two")
+
+	var values []string
+	assert.NoError(t, m.Range(func(key int, value string) error {
+		values = append(values, value)
+		return nil
+	}))
+	assert.Equal(t, []string{"one", "two", "three"}, values)
+
+	errStop := errors.New("stop")
+	assert.ErrorIs(t, m.Range(func(key int, value string) error {
+		return errStop
+	}), errStop)
+}
+
+func TestSortedMap_FloorCeiling(t *testing.T) {
+	m := NewSortedMap[int, string, CompareFunc[int]](CompareNumber[int])
+	m.Put(10, "ten")
+	m.Put(20, "twenty")
+
+	key, value, ok := m.Floor(15)
+	assert.True(t, ok)
+	assert.Equal(t, 10, key)
+	assert.Equal(t, "ten", value)
+
+	key, _, ok = m.Floor(20)
+	assert.True(t, ok)
+	assert.Equal(t, 20, key)
+
+	_, _, ok = m.Floor(5)
+	assert.False(t, ok)
+
+	key, value, ok = m.Ceiling(15)
+	assert.True(t, ok)
+	assert.Equal(t, 20, key)
+	assert.Equal(t, "twenty", value)
+
+	_, _, ok = m.Ceiling(25)
+	assert.False(t, ok)
+}
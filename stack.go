@@ -4,13 +4,15 @@ import "sync"
 
 // Stack is an interface of stack
 type Stack[T any] interface {
+	Len() int
 	Empty() bool
 	Push(T)
 	Top() T
 	Pop() T
 }
 
-// StackImpl is an implementation of stack
+// StackImpl is an implementation of stack. The stack grows at the tail
+// of the vector, so the top of the stack is the last vector element.
 type StackImpl[T any] struct {
 	Vector *Impl[T]
 }
@@ -42,6 +44,11 @@ func (s *StackImpl[T]) WithLocker(locker sync.Locker) *StackImpl[T] {
 	return s
 }
 
+// Len returns the number of elements in the stack
+func (s *StackImpl[T]) Len() int {
+	return s.Vector.Len()
+}
+
 // Empty returns true if the stack is empty
 func (s *StackImpl[T]) Empty() bool {
 	return s.Vector.Len() == 0
@@ -49,16 +56,131 @@ func (s *StackImpl[T]) Empty() bool {
 
 // Push pushes a value onto the stack
 func (s *StackImpl[T]) Push(value T) {
-	s.Vector.Insert(0, value)
+	s.Vector.Append(value)
+}
+
+// PushN pushes values onto the stack one by one, so the last value becomes the top
+func (s *StackImpl[T]) PushN(values ...T) {
+	s.Vector.Append(values...)
 }
 
 // Top returns the the top element of stack. This
 // method is not changes stack content
 func (s *StackImpl[T]) Top() T {
-	return s.Vector.First()
+	return s.Vector.Last()
+}
+
+// peek returns the n-th element from the top of the stack
+func (s *StackImpl[T]) peek(n int) T {
+	if n < 0 || n >= s.Vector.len() {
+		panic(ErrIndexOutOfRange)
+	}
+
+	return s.Vector.data[s.Vector.len()-1-n]
+}
+
+// Peek returns the n-th element from the top of the stack, Peek(0) is the Top.
+// This method is not changes stack content
+func (s *StackImpl[T]) Peek(n int) T {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	return s.peek(n)
 }
 
 // Pop removes the element at the top of the stack
 func (s *StackImpl[T]) Pop() T {
-	return s.Vector.Remove(0)
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	return s.Vector.removeLast()
+}
+
+// TryPop removes the element at the top of the stack.
+//
+// Returns the element and true, or zero value and false if the stack is empty.
+func (s *StackImpl[T]) TryPop() (ret T, ok bool) {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	if s.Vector.len() == 0 {
+		return
+	}
+	return s.Vector.removeLast(), true
+}
+
+// popN removes n elements from the top of the stack
+func (s *StackImpl[T]) popN(n int) []T {
+	if n < 0 || n > s.Vector.len() {
+		panic(ErrIndexOutOfRange)
+	}
+
+	ret := make([]T, 0, n)
+	for i := 0; i < n; i++ {
+		ret = append(ret, s.Vector.removeLast())
+	}
+	return ret
+}
+
+// PopN removes n elements from the top of the stack and returns them
+// in the pop order (the former top first).
+func (s *StackImpl[T]) PopN(n int) []T {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	return s.popN(n)
+}
+
+// Dup pushes a copy of the top element onto the stack
+func (s *StackImpl[T]) Dup() {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	s.Vector.append(s.Vector.last())
+}
+
+// swap exchanges two top elements of the stack
+func (s *StackImpl[T]) swap() {
+	count := s.Vector.len()
+	if count < 2 {
+		panic(ErrIndexOutOfRange)
+	}
+
+	s.Vector.data[count-1], s.Vector.data[count-2] = s.Vector.data[count-2], s.Vector.data[count-1]
+}
+
+// Swap exchanges two top elements of the stack
+func (s *StackImpl[T]) Swap() {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	s.swap()
+}
+
+// Clear removes all elements from the stack
+func (s *StackImpl[T]) Clear() {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	s.Vector.data = make([]T, 0)
+}
+
+// xrange calls the callback for each element from the top to the bottom
+func (s *StackImpl[T]) xrange(callback func(index int, value T) error) error {
+	count := s.Vector.len()
+	for index := 0; index < count; index++ {
+		if err := callback(index, s.Vector.data[count-1-index]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Range enumerates stack elements from the top to the bottom. The top element has index 0.
+// If the callback returns an error, the iteration stops and returns the error.
+func (s *StackImpl[T]) Range(callback func(index int, value T) error) error {
+	s.Vector.Locker().Lock()
+	defer s.Vector.Locker().Unlock()
+
+	return s.xrange(callback)
 }
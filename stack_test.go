@@ -1,6 +1,8 @@
 package vector
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -18,3 +20,78 @@ func TestStack(t *testing.T) {
 	assert.Equal(t, 1234, stack.Pop())
 	assert.True(t, stack.Empty())
 }
+
+func TestStack_Len(t *testing.T) {
+	var stack Stack[int] = NewStack[int]().WithLocker(&sync.Mutex{})
+	assert.Equal(t, 0, stack.Len())
+	stack.Push(1)
+	stack.Push(2)
+	assert.Equal(t, 2, stack.Len())
+	stack.Pop()
+	assert.Equal(t, 1, stack.Len())
+}
+
+func TestStack_PushNPopN(t *testing.T) {
+	stack := NewStack[int]()
+	stack.PushN(1, 2, 3, 4)
+	assert.Equal(t, 4, stack.Top())
+	assert.Equal(t, []int{4, 3}, stack.PopN(2))
+	assert.Equal(t, []int{2, 1}, stack.PopN(2))
+	assert.Equal(t, []int{}, stack.PopN(0))
+	assert.Panics(t, func() {
+		stack.PopN(1)
+	})
+	assert.Panics(t, func() {
+		stack.Pop()
+	})
+}
+
+func TestStack_Peek(t *testing.T) {
+	stack := NewStack[int]()
+	stack.PushN(1, 2, 3)
+	assert.Equal(t, 3, stack.Peek(0))
+	assert.Equal(t, 2, stack.Peek(1))
+	assert.Equal(t, 1, stack.Peek(2))
+	assert.Panics(t, func() {
+		stack.Peek(3)
+	})
+	assert.Equal(t, 3, stack.Len())
+}
+
+func TestStack_DupSwapClear(t *testing.T) {
+	stack := NewStack[int]()
+	stack.PushN(1, 2)
+	stack.Dup()
+	assert.Equal(t, []int{1, 2, 2}, stack.Vector.Data())
+	stack.Push(3)
+	stack.Swap()
+	assert.Equal(t, []int{1, 2, 3, 2}, stack.Vector.Data())
+	stack.Clear()
+	assert.True(t, stack.Empty())
+	assert.Panics(t, func() {
+		stack.Dup()
+	})
+	stack.Push(1)
+	assert.Panics(t, func() {
+		stack.Swap()
+	})
+}
+
+func TestStack_Range(t *testing.T) {
+	stack := NewStack[int]()
+	stack.PushN(1, 2, 3)
+
+	var indexes, values []int
+	assert.NoError(t, stack.Range(func(index int, value int) error {
+		indexes = append(indexes, index)
+		values = append(values, value)
+		return nil
+	}))
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+	assert.Equal(t, []int{3, 2, 1}, values)
+
+	errStop := errors.New("stop")
+	assert.ErrorIs(t, stack.Range(func(index int, value int) error {
+		return errStop
+	}), errStop)
+}
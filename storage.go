@@ -0,0 +1,205 @@
+package vector
+
+// orderedStorage is an interface of the positional elements storage what Order and Set
+// are written against. The algorithms walk the storage by position (get and xrange),
+// Data may copy all elements. The storage is not thread safe, the owner container locks it.
+// The default implementation is the vector (Impl), the counted B-tree can be selected
+// by WithBTree option.
+type orderedStorage[T any] interface {
+	// len returns the count of elements
+	len() int
+	// get returns the element at index
+	get(index uint) T
+	// set replaces the element at index
+	set(index uint, value T)
+	// insert inserts elements at index
+	insert(index uint, args ...T)
+	// remove removes the element at index
+	remove(index uint) T
+	// removeRange removes the elements in [lo, hi) range
+	removeRange(lo, hi int)
+	// append appends elements to the end
+	append(args ...T)
+	// truncate removes all elements starting from count
+	truncate(count int)
+	// reset replaces all elements by data, the storage may take the slice ownership
+	reset(data []T)
+	// xrange calls the callback for each element
+	xrange(callback func(index int, value T) error) error
+	// newEmpty creates a new empty storage of the same type
+	newEmpty() orderedStorage[T]
+	// Data returns the elements, the slice may be a copy
+	Data() []T
+}
+
+// orderOptions are the order (and set) construction options
+type orderOptions struct {
+	bTreeDegree   int
+	validation    bool
+	duplicates    OrderDuplicatePolicy
+	hasDuplicates bool
+}
+
+// OrderOption is an order (and set) construction option
+type OrderOption func(*orderOptions)
+
+// WithBTree makes the container keep elements in the counted B-tree instead of the vector,
+// so adding an element costs O(log n) instead of O(n) copying.
+//
+// degree: the B-tree minimal degree, every node except the root keeps from degree to 2*degree entries.
+func WithBTree(degree int) OrderOption {
+	return func(options *orderOptions) {
+		options.bTreeDegree = degree
+	}
+}
+
+// WithValidation turns on the debug mode: the container validates its invariants after each
+// mutation and panics with the validation error. It is intended for tests, every validation
+// costs O(n).
+func WithValidation() OrderOption {
+	return func(options *orderOptions) {
+		options.validation = true
+	}
+}
+
+// WithDuplicatePolicy sets the policy of adding elements equal to the stored ones,
+// the default policy is OrderDuplicatesAllow.
+func WithDuplicatePolicy(policy OrderDuplicatePolicy) OrderOption {
+	return func(options *orderOptions) {
+		options.duplicates = policy
+		options.hasDuplicates = true
+	}
+}
+
+// makeOrderOptions applies options to the default options
+func makeOrderOptions(options ...OrderOption) (ret orderOptions) {
+	for _, option := range options {
+		option(&ret)
+	}
+	return
+}
+
+// newOrderedStorage creates a storage in accordance to options
+func newOrderedStorage[T any](options ...OrderOption) orderedStorage[T] {
+	opts := makeOrderOptions(options...)
+	if opts.bTreeDegree > 0 {
+		return newBTree[T](opts.bTreeDegree)
+	}
+	return NewVector[T]()
+}
+
+// reversedStorage is a view of the storage in the reverse order, it shares the elements
+// with the base storage. Data returns a copy.
+type reversedStorage[T any] struct {
+	base orderedStorage[T]
+}
+
+// reverseStorage returns the reverse view of the storage, the view of view is the base storage
+func reverseStorage[T any](storage orderedStorage[T]) orderedStorage[T] {
+	if view, ok := storage.(*reversedStorage[T]); ok {
+		return view.base
+	}
+	return &reversedStorage[T]{base: storage}
+}
+
+// index converts the view index to the base storage index
+func (r *reversedStorage[T]) index(index uint) uint {
+	length := r.base.len()
+	if int(index) >= length {
+		panic(ErrIndexOutOfRange)
+	}
+	return uint(length-1) - index
+}
+
+// len returns the count of elements
+func (r *reversedStorage[T]) len() int {
+	return r.base.len()
+}
+
+// get returns the element at index
+func (r *reversedStorage[T]) get(index uint) T {
+	return r.base.get(r.index(index))
+}
+
+// set replaces the element at index
+func (r *reversedStorage[T]) set(index uint, value T) {
+	r.base.set(r.index(index), value)
+}
+
+// insert inserts elements at index
+func (r *reversedStorage[T]) insert(index uint, args ...T) {
+	length := r.base.len()
+	if int(index) > length {
+		panic(ErrIndexOutOfRange)
+	}
+	reversed := make([]T, len(args))
+	for i, value := range args {
+		reversed[len(args)-1-i] = value
+	}
+	r.base.insert(uint(length)-index, reversed...)
+}
+
+// remove removes the element at index
+func (r *reversedStorage[T]) remove(index uint) T {
+	if r.base.len() == 0 {
+		panic(ErrEmptyVector)
+	}
+	return r.base.remove(r.index(index))
+}
+
+// removeRange removes the elements in [lo, hi) range
+func (r *reversedStorage[T]) removeRange(lo, hi int) {
+	length := r.base.len()
+	if lo < 0 || lo > hi || hi > length {
+		panic(ErrIndexOutOfRange)
+	}
+	r.base.removeRange(length-hi, length-lo)
+}
+
+// append appends elements to the end
+func (r *reversedStorage[T]) append(args ...T) {
+	r.insert(uint(r.base.len()), args...)
+}
+
+// truncate removes all elements starting from count
+func (r *reversedStorage[T]) truncate(count int) {
+	length := r.base.len()
+	if count < 0 || count > length {
+		panic(ErrIndexOutOfRange)
+	}
+	r.base.removeRange(0, length-count)
+}
+
+// reset replaces all elements by data, the slice is reversed in place
+func (r *reversedStorage[T]) reset(data []T) {
+	for lo, hi := 0, len(data)-1; lo < hi; lo, hi = lo+1, hi-1 {
+		data[lo], data[hi] = data[hi], data[lo]
+	}
+	r.base.reset(data)
+}
+
+// xrange calls the callback for each element
+func (r *reversedStorage[T]) xrange(callback func(index int, value T) error) error {
+	length := r.base.len()
+	for index := 0; index < length; index++ {
+		if err := callback(index, r.base.get(uint(length-1-index))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newEmpty creates a new empty storage of the base storage type
+func (r *reversedStorage[T]) newEmpty() orderedStorage[T] {
+	return r.base.newEmpty()
+}
+
+// Data returns a copy of the elements in the reverse order
+func (r *reversedStorage[T]) Data() []T {
+	ret := make([]T, 0, r.base.len())
+	r.xrange(func(_ int, value T) error {
+		ret = append(ret, value)
+		return nil
+	})
+	return ret
+}
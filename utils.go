@@ -9,3 +9,109 @@ func NewStringsSet(data []string) (ret *SetImpl[string, CompareFunc[string]]) {
 	ret.Add(data...)
 	return ret
 }
+
+// NewNumberSet creates a new SetImpl[N, CompareFunc[N]] from the given numbers slice.
+//
+// data: A slice of numbers to add to the set.
+// returns: A pointer to the created SetImpl[N, CompareFunc[N]].
+func NewNumberSet[N Number](data []N) (ret *SetImpl[N, CompareFunc[N]]) {
+	ret = NewSet[N, CompareFunc[N]](CompareNumber[N])
+	ret.Add(data...)
+	return ret
+}
+
+// NewSetFromMapKeys creates a new set of the map keys.
+//
+// data: A map whose keys are added to the set.
+// compareFunc: the function used to compare elements.
+// returns: A pointer to the created set.
+func NewSetFromMapKeys[K comparable, V any, C CompareFunc[K]](data map[K]V, compareFunc C) (ret *SetImpl[K, C]) {
+	ret = NewSet[K](compareFunc)
+	for key := range data {
+		ret.add(key)
+	}
+	return ret
+}
+
+// NewSetFromMapValues creates a new set of the map values, the repeated values are added once.
+//
+// data: A map whose values are added to the set.
+// compareFunc: the function used to compare elements.
+// returns: A pointer to the created set.
+func NewSetFromMapValues[K comparable, V any, C CompareFunc[V]](data map[K]V, compareFunc C) (ret *SetImpl[V, C]) {
+	ret = NewSet[V](compareFunc)
+	for _, value := range data {
+		ret.add(value)
+	}
+	return ret
+}
+
+// NewSetFromVector creates a new set of the vector elements.
+//
+// vector: A vector whose elements are added to the set.
+// compareFunc: the function used to compare elements.
+// returns: A pointer to the created set.
+func NewSetFromVector[T any, C CompareFunc[T]](vector *Impl[T], compareFunc C) (ret *SetImpl[T, C]) {
+	vector.Locker().Lock()
+	defer vector.Locker().Unlock()
+
+	ret = NewSet[T](compareFunc)
+	ret.add(vector.Data()...)
+	return ret
+}
+
+// NewSetFromIterator creates a new set of the elements produced by the push iterator.
+// The iterator calls yield for every element and stops when yield returns false, so
+// the functions of iter.Seq[T] shape are accepted as is.
+//
+// seq: An iterator whose elements are added to the set.
+// compareFunc: the function used to compare elements.
+// returns: A pointer to the created set.
+func NewSetFromIterator[T any, C CompareFunc[T]](seq func(yield func(T) bool), compareFunc C) (ret *SetImpl[T, C]) {
+	ret = NewSet[T](compareFunc)
+	seq(func(value T) bool {
+		ret.add(value)
+		return true
+	})
+	return ret
+}
+
+// NewSetFromChan creates a new set of the elements received from the channel.
+// It reads the channel until it is closed.
+//
+// in: A channel whose elements are added to the set.
+// compareFunc: the function used to compare elements.
+// returns: A pointer to the created set.
+func NewSetFromChan[T any, C CompareFunc[T]](in <-chan T, compareFunc C) (ret *SetImpl[T, C]) {
+	ret = NewSet[T](compareFunc)
+	for value := range in {
+		ret.add(value)
+	}
+	return ret
+}
+
+// ToSlice returns a copy of the set elements in the set order.
+//
+// set: A set to convert.
+// returns: A new slice of the set elements.
+func ToSlice[T any, C CompareFunc[T]](set *SetImpl[T, C]) []T {
+	set.Order.Locker().Lock()
+	defer set.Order.Locker().Unlock()
+
+	return append(make([]T, 0, set.Order.storage.len()), set.Order.storage.Data()...)
+}
+
+// ToMap returns a map whose keys are the set elements.
+//
+// set: A set to convert.
+// returns: A new map of the set elements.
+func ToMap[T comparable, C CompareFunc[T]](set *SetImpl[T, C]) map[T]struct{} {
+	set.Order.Locker().Lock()
+	defer set.Order.Locker().Unlock()
+
+	ret := make(map[T]struct{}, set.Order.storage.len())
+	for _, value := range set.Order.storage.Data() {
+		ret[value] = struct{}{}
+	}
+	return ret
+}
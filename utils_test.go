@@ -0,0 +1,71 @@
+package vector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUtils_NewNumberSet(t *testing.T) {
+	ints := NewNumberSet([]int{3, 1, 2, 3})
+	assert.Equal(t, []int{1, 2, 3}, ints.Data())
+
+	floats := NewNumberSet([]float64{2.5, -1, 2.5})
+	assert.Equal(t, []float64{-1, 2.5}, floats.Data())
+
+	assert.True(t, NewNumberSet[uint32](nil).Empty())
+}
+
+func TestUtils_NewSetFromMap(t *testing.T) {
+	data := map[string]int{"c": 1, "a": 2, "b": 1}
+
+	keys := NewSetFromMapKeys(data, CompareFunc[string](CompareString[string]))
+	assert.Equal(t, []string{"a", "b", "c"}, keys.Data())
+
+	values := NewSetFromMapValues(data, CompareFunc[int](CompareNumber[int]))
+	assert.Equal(t, []int{1, 2}, values.Data())
+}
+
+func TestUtils_NewSetFromVector(t *testing.T) {
+	v := NewVector[int]()
+	v.Append(5, 1, 5, 3)
+
+	s := NewSetFromVector(v, CompareFunc[int](CompareNumber[int]))
+	assert.Equal(t, []int{1, 3, 5}, s.Data())
+	assert.Equal(t, []int{5, 1, 5, 3}, v.Data())
+}
+
+func TestUtils_NewSetFromIterator(t *testing.T) {
+	seq := func(yield func(int) bool) {
+		for _, value := range []int{4, 2, 4, 8} {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+
+	s := NewSetFromIterator(seq, CompareFunc[int](CompareNumber[int]))
+	assert.Equal(t, []int{2, 4, 8}, s.Data())
+}
+
+func TestUtils_NewSetFromChan(t *testing.T) {
+	in := make(chan string, 4)
+	in <- "b"
+	in <- "a"
+	in <- "b"
+	close(in)
+
+	s := NewSetFromChan(in, CompareFunc[string](CompareString[string]))
+	assert.Equal(t, []string{"a", "b"}, s.Data())
+}
+
+func TestUtils_ToSliceToMap(t *testing.T) {
+	s := NewNumberSet([]int{3, 1, 2})
+
+	slice := ToSlice(s)
+	assert.Equal(t, []int{1, 2, 3}, slice)
+	slice[0] = 100
+	assert.Equal(t, []int{1, 2, 3}, s.Data())
+
+	assert.Equal(t, map[int]struct{}{1: {}, 2: {}, 3: {}}, ToMap(s))
+}
@@ -260,6 +260,53 @@ func (v *Impl[T]) Remove(index uint) (ret T) {
 	return v.remove(index)
 }
 
+// removeRange removes the elements in [lo, hi) range in place
+func (v *Impl[T]) removeRange(lo, hi int) {
+	if lo < 0 || lo > hi || hi > len(v.data) {
+		panic(ErrIndexOutOfRange)
+	}
+
+	copy(v.data[lo:], v.data[hi:])
+	v.data = clearTail(v.data, len(v.data)-(hi-lo))
+}
+
+// removeLast removes the last element in place without copying the data
+func (v *Impl[T]) removeLast() (ret T) {
+	if len(v.data) == 0 {
+		panic(ErrEmptyVector)
+	}
+
+	var zero T
+	last := len(v.data) - 1
+	ret = v.data[last]
+	v.data[last] = zero
+	v.data = v.data[:last]
+	return
+}
+
+// truncate removes all elements starting from count in place
+func (v *Impl[T]) truncate(count int) {
+	if count < 0 || count > len(v.data) {
+		panic(ErrIndexOutOfRange)
+	}
+
+	var zero T
+	for index := count; index < len(v.data); index++ {
+		v.data[index] = zero
+	}
+	v.data = v.data[:count]
+}
+
+// reset replaces all elements by data, the vector keeps the slice
+func (v *Impl[T]) reset(data []T) {
+	v.data = data
+}
+
+// newEmpty creates a new empty vector
+func (v *Impl[T]) newEmpty() orderedStorage[T] {
+	return NewVector[T]()
+}
+
 // xrange calls the callback for each element
 func (v *Impl[T]) xrange(callback func(index int, value T) error) error {
 	for index, value := range v.data {